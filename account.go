@@ -0,0 +1,84 @@
+package fasapay
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var accountNumberRegexp = regexp.MustCompile(`^FP[0-9]+$`)
+
+//AccountNumber type - FasaPay account number (format: FPnnnnn)
+type AccountNumber string
+
+//ParseAccountNumber Parse and validate account number
+func ParseAccountNumber(value string) (AccountNumber, error) {
+	account := AccountNumber(value).Normalize()
+	if err := account.Validate(); err != nil {
+		return "", err
+	}
+	return account, nil
+}
+
+//MustParseAccountNumber Parse account number or panic
+func MustParseAccountNumber(value string) AccountNumber {
+	account, err := ParseAccountNumber(value)
+	if err != nil {
+		panic(err)
+	}
+	return account
+}
+
+//Normalize method - upper-cased account number without surrounding spaces
+func (a AccountNumber) Normalize() AccountNumber {
+	return AccountNumber(strings.ToUpper(strings.TrimSpace(string(a))))
+}
+
+//Validate method - check is account number in FPnnnnn format
+func (a AccountNumber) Validate() error {
+	account := a.Normalize()
+	if account == "" {
+		return fmt.Errorf(`account number is empty`)
+	}
+	if !accountNumberRegexp.MatchString(string(account)) {
+		return fmt.Errorf(`account number "%s" is not valid, FPnnnnn expected`, a)
+	}
+	return nil
+}
+
+//String method
+func (a AccountNumber) String() string {
+	return string(a)
+}
+
+//lenient method - normalized FPnnnnn account number, other values (e.g. bank accounts of redeem/topup transactions) as is
+func (a AccountNumber) lenient() AccountNumber {
+	account := a.Normalize()
+	if accountNumberRegexp.MatchString(string(account)) {
+		return account
+	}
+	return a
+}
+
+//MarshalText method - account numbers are never rejected here, they are validated on the request path only
+func (a AccountNumber) MarshalText() ([]byte, error) {
+	return []byte(a.lenient()), nil
+}
+
+//UnmarshalText method - FPnnnnn account numbers are normalized, other values are kept as is
+func (a *AccountNumber) UnmarshalText(text []byte) error {
+	*a = AccountNumber(text).lenient()
+	return nil
+}
+
+//normalizeAccountNumbers Normalize and validate account numbers
+func normalizeAccountNumbers(accounts []AccountNumber) ([]AccountNumber, error) {
+	result := make([]AccountNumber, len(accounts))
+	for i, account := range accounts {
+		if err := account.Validate(); err != nil {
+			return nil, err
+		}
+		result[i] = account.Normalize()
+	}
+	return result, nil
+}
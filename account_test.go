@@ -0,0 +1,88 @@
+package fasapay
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type AccountNumberTestSuite struct {
+	suite.Suite
+}
+
+func (suite *AccountNumberTestSuite) TestParseAccountNumber() {
+	result, err := ParseAccountNumber(" fp00001 ")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), AccountNumber("FP00001"), result)
+	assert.Equal(suite.T(), "FP00001", result.String())
+}
+
+func (suite *AccountNumberTestSuite) TestParseAccountNumberInvalid() {
+	_, err := ParseAccountNumber("")
+	assert.Equal(suite.T(), `account number is empty`, err.Error())
+	_, err = ParseAccountNumber("FP")
+	assert.Equal(suite.T(), `account number "FP" is not valid, FPnnnnn expected`, err.Error())
+	_, err = ParseAccountNumber("FP12a45")
+	assert.Equal(suite.T(), `account number "FP12A45" is not valid, FPnnnnn expected`, err.Error())
+	_, err = ParseAccountNumber("XX12345")
+	assert.Error(suite.T(), err)
+}
+
+func (suite *AccountNumberTestSuite) TestMustParseAccountNumber() {
+	assert.Equal(suite.T(), AccountNumber("FP12345"), MustParseAccountNumber("fp12345"))
+	assert.Panics(suite.T(), func() {
+		MustParseAccountNumber("12345")
+	})
+}
+
+func (suite *AccountNumberTestSuite) TestMarshalXml() {
+	bytes, err := xml.Marshal([]AccountNumber{"fp00001", " FP00002"})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), `<AccountNumber>FP00001</AccountNumber><AccountNumber>FP00002</AccountNumber>`, string(bytes))
+}
+
+func (suite *AccountNumberTestSuite) TestMarshalLenient() {
+	bytes, err := xml.Marshal([]AccountNumber{"BCA 123", "12345"})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), `<AccountNumber>BCA 123</AccountNumber><AccountNumber>12345</AccountNumber>`, string(bytes))
+	bytes, err = json.Marshal(AccountNumber("BCA 123"))
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), `"BCA 123"`, string(bytes))
+}
+
+func (suite *AccountNumberTestSuite) TestMarshalResponseNonAccount() {
+	var detail GetHistoryResponseDetailParams
+	err := xml.Unmarshal([]byte(`<detail><to>BCA 123</to><from>fp12049</from></detail>`), &detail)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), AccountNumber("BCA 123"), detail.To)
+	assert.Equal(suite.T(), AccountNumber("FP12049"), detail.From)
+	bytes, err := json.Marshal(detail)
+	assert.NoError(suite.T(), err)
+	assert.Contains(suite.T(), string(bytes), `"to":"BCA 123"`)
+	assert.Contains(suite.T(), string(bytes), `"from":"FP12049"`)
+}
+
+func (suite *AccountNumberTestSuite) TestMarshalEmpty() {
+	bytes, err := json.Marshal(AccountNumber(""))
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), `""`, string(bytes))
+}
+
+func (suite *AccountNumberTestSuite) TestUnmarshal() {
+	var detail GetHistoryResponseDetailParams
+	err := xml.Unmarshal([]byte(`<detail><to> fp10500 </to><from>FP12049</from></detail>`), &detail)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), AccountNumber("FP10500"), detail.To)
+	assert.Equal(suite.T(), AccountNumber("FP12049"), detail.From)
+
+	var account AccountNumber
+	err = json.Unmarshal([]byte(`"fp00001"`), &account)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), AccountNumber("FP00001"), account)
+}
+
+func TestAccountNumberTestSuite(t *testing.T) {
+	suite.Run(t, new(AccountNumberTestSuite))
+}
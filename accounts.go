@@ -2,9 +2,11 @@ package fasapay
 
 import (
 	"context"
+	"encoding/json"
 	"encoding/xml"
 	"fmt"
 	"net/http"
+	"sort"
 )
 
 //GetBalancesRequest struct
@@ -13,6 +15,11 @@ type GetBalancesRequest struct {
 	Balances []CurrencyCode `xml:"balance" json:"balances"`
 }
 
+//getItemsCount method
+func (r *GetBalancesRequest) getItemsCount() int {
+	return len(r.Balances)
+}
+
 //GetBalancesResponse struct
 type GetBalancesResponse struct {
 	ResponseBody
@@ -21,14 +28,85 @@ type GetBalancesResponse struct {
 
 //GetBalancesResponseParams struct
 type GetBalancesResponseParams struct {
-	IDR float64 `xml:"IDR" json:"IDR"`
-	USD float64 `xml:"USD" json:"USD"`
+	Amounts map[CurrencyCode]Amount //balances of every currency returned in <balance> element
+}
+
+//UnmarshalXML method implementation - capture every child element of <balance> as currency balance
+func (p *GetBalancesResponseParams) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	p.Amounts = map[CurrencyCode]Amount{}
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch element := token.(type) {
+		case xml.StartElement:
+			var amount Amount
+			if err := d.DecodeElement(&amount, &element); err != nil {
+				return err
+			}
+			p.Amounts[CurrencyCode(element.Name.Local).Normalize()] = amount
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+//MarshalJSON method implementation
+func (p *GetBalancesResponseParams) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.Amounts)
+}
+
+//UnmarshalJSON method implementation
+func (p *GetBalancesResponseParams) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &p.Amounts)
+}
+
+//Get method - balance by currency code (false if balance is not returned)
+func (p *GetBalancesResponseParams) Get(code CurrencyCode) (Amount, bool) {
+	if p == nil {
+		return Amount{}, false
+	}
+	amount, ok := p.Amounts[code.Normalize()]
+	return amount, ok
+}
+
+//Currencies method - currency codes of returned balances
+func (p *GetBalancesResponseParams) Currencies() []CurrencyCode {
+	if p == nil {
+		return []CurrencyCode{}
+	}
+	codes := make([]CurrencyCode, 0, len(p.Amounts))
+	for code := range p.Amounts {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool {
+		return codes[i] < codes[j]
+	})
+	return codes
+}
+
+//IDR method - IDR balance (zero if balance is not returned)
+func (p *GetBalancesResponseParams) IDR() Amount {
+	amount, _ := p.Get(CurrencyCodeIDR)
+	return amount
+}
+
+//USD method - USD balance (zero if balance is not returned)
+func (p *GetBalancesResponseParams) USD() Amount {
+	amount, _ := p.Get(CurrencyCodeUSD)
+	return amount
 }
 
 //GetAccountsRequest struct
 type GetAccountsRequest struct {
 	RequestParams
-	Accounts []string `xml:"account" json:"accounts"`
+	Accounts []AccountNumber `xml:"account" json:"accounts"`
+}
+
+//getItemsCount method
+func (r *GetAccountsRequest) getItemsCount() int {
+	return len(r.Accounts)
 }
 
 //GetAccountsResponse struct
@@ -39,10 +117,10 @@ type GetAccountsResponse struct {
 
 //GetAccountsResponseParams struct
 type GetAccountsResponseParams struct {
-	XMLName  xml.Name `xml:"account" json:"-"`
-	FullName string   `xml:"fullname" json:"fullname"`
-	Account  string   `xml:"account" json:"account"`
-	Status   string   `xml:"status" json:"status"`
+	XMLName  xml.Name      `xml:"account" json:"-"`
+	FullName string        `xml:"fullname" json:"fullname"`
+	Account  AccountNumber `xml:"account" json:"account"`
+	Status   string        `xml:"status" json:"status"`
 }
 
 //AccountsResource struct
@@ -78,23 +156,19 @@ type AccountsResource struct {
 //</fasa_request>
 //
 func (r *AccountsResource) GetBalances(currencies []CurrencyCode, ctx context.Context, attributes *RequestParamsAttributes) (*GetBalancesResponse, *http.Response, error) {
-	baseRequestParams := r.buildRequestParams(attributes)
-	requestParams := &GetBalancesRequest{baseRequestParams, currencies}
-	bytesRequest, err := r.marshalRequestParams(requestParams)
-	if err != nil {
-		return nil, nil, fmt.Errorf("AccountsResource.GetBalances error: %v", err)
-	}
-	rsp, err := r.tr.SendRequest(ctx, bytesRequest)
+	currencies, err := normalizeCurrencies(currencies)
 	if err != nil {
-		return nil, nil, fmt.Errorf("AccountsResource.GetBalances error: %v", err)
+		return nil, nil, fmt.Errorf("AccountsResource.GetBalances error: %w", err)
 	}
+	baseRequestParams := r.buildRequestParams(attributes)
+	requestParams := &GetBalancesRequest{baseRequestParams, currencies}
 	var result GetBalancesResponse
-	err = r.unmarshalResponse(rsp, &result)
+	rsp, err := r.execute(ctx, OperationGetBalances, requestParams, &result)
 	if err != nil {
-		return nil, rsp, fmt.Errorf("AccountsResource.GetBalances error: %v", err)
+		return nil, rsp, fmt.Errorf("AccountsResource.GetBalances error: %w", err)
 	}
 	if !result.IsSuccess() {
-		return &result, rsp, fmt.Errorf(result.GetError())
+		return &result, rsp, result.GetAPIError()
 	}
 	return &result, rsp, nil
 }
@@ -126,24 +200,20 @@ func (r *AccountsResource) GetBalances(currencies []CurrencyCode, ctx context.Co
 //    <account>FP00002</account>
 //</fasa_request>
 //
-func (r *AccountsResource) GetAccounts(accounts []string, ctx context.Context, attributes *RequestParamsAttributes) (*GetAccountsResponse, *http.Response, error) {
-	baseRequestParams := r.buildRequestParams(attributes)
-	requestParams := &GetAccountsRequest{baseRequestParams, accounts}
-	bytesRequest, err := r.marshalRequestParams(requestParams)
+func (r *AccountsResource) GetAccounts(accounts []AccountNumber, ctx context.Context, attributes *RequestParamsAttributes) (*GetAccountsResponse, *http.Response, error) {
+	accounts, err := normalizeAccountNumbers(accounts)
 	if err != nil {
-		return nil, nil, fmt.Errorf("AccountsResource.GetAccounts error: %v", err)
-	}
-	rsp, err := r.tr.SendRequest(ctx, bytesRequest)
-	if err != nil {
-		return nil, nil, fmt.Errorf("AccountsResource.GetAccounts error: %v", err)
+		return nil, nil, fmt.Errorf("AccountsResource.GetAccounts error: %w", err)
 	}
+	baseRequestParams := r.buildRequestParams(attributes)
+	requestParams := &GetAccountsRequest{baseRequestParams, accounts}
 	var result GetAccountsResponse
-	err = r.unmarshalResponse(rsp, &result)
+	rsp, err := r.execute(ctx, OperationGetAccounts, requestParams, &result)
 	if err != nil {
-		return nil, rsp, fmt.Errorf("AccountsResource.GetAccounts error: %v", err)
+		return nil, rsp, fmt.Errorf("AccountsResource.GetAccounts error: %w", err)
 	}
 	if !result.IsSuccess() {
-		return &result, rsp, fmt.Errorf(result.GetError())
+		return &result, rsp, result.GetAPIError()
 	}
 	return &result, rsp, nil
 }
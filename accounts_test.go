@@ -4,12 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"encoding/xml"
+	"errors"
 	"github.com/jarcoal/httpmock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"io/ioutil"
 	"net/http"
 	"testing"
+	"time"
 )
 
 type AccountsTestSuite struct {
@@ -17,7 +19,7 @@ type AccountsTestSuite struct {
 }
 
 func (suite *AccountsTestSuite) TestGetAccountsRequestMarshalXmlSuccess() {
-	xmlRequest := &GetAccountsRequest{RequestParams: BuildStubRequest(), Accounts: []string{"FP00001", "FP00002"}}
+	xmlRequest := &GetAccountsRequest{RequestParams: BuildStubRequest(), Accounts: []AccountNumber{"FP00001", "FP00002"}}
 	bytes, err := xml.Marshal(xmlRequest)
 	expected := `<fasa_request id="1234567"><auth><api_key>11123548cd3a5e5613325132112becf</api_key><token>e910361e42dafdfd100b19701c2ef403858cab640fd699afc67b78c7603ddb1b</token></auth><account>FP00001</account><account>FP00002</account></fasa_request>`
 	assert.NoError(suite.T(), err)
@@ -80,6 +82,36 @@ func (suite *AccountsTestSuite) TestGetBalancesResponseMarshalJsonError() {
 	assert.Equal(suite.T(), expected, string(bytes))
 }
 
+func (suite *AccountsTestSuite) TestGetBalancesResponseUnmarshalXmlDynamicCurrencies() {
+	var response GetBalancesResponse
+	body := `<fasa_response id="1234567" date_time="2013-01-01T10:58:43+07:00"><balance><IDR>19092587.45</IDR><USD>3987.31</USD><eur>10.5</eur></balance></fasa_response>`
+	err := xml.Unmarshal([]byte(body), &response)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []CurrencyCode{"EUR", CurrencyCodeIDR, CurrencyCodeUSD}, response.Balances.Currencies())
+	assert.Equal(suite.T(), MustParseAmount("19092587.45"), response.Balances.IDR())
+	assert.Equal(suite.T(), MustParseAmount("3987.31"), response.Balances.USD())
+	amount, ok := response.Balances.Get("eur")
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), MustParseAmount("10.5"), amount)
+	_, ok = response.Balances.Get("CHY")
+	assert.False(suite.T(), ok)
+}
+
+func (suite *AccountsTestSuite) TestGetBalancesResponseUnmarshalXmlInvalidAmount() {
+	var response GetBalancesResponse
+	body := `<fasa_response id="1234567" date_time="2013-01-01T10:58:43+07:00"><balance><IDR>foo</IDR></balance></fasa_response>`
+	err := xml.Unmarshal([]byte(body), &response)
+	assert.Error(suite.T(), err)
+}
+
+func (suite *AccountsTestSuite) TestGetBalancesResponseParamsUnmarshalJson() {
+	var result GetBalancesResponseParams
+	err := json.Unmarshal([]byte(`{"IDR":19092587.45,"USD":"3987.31"}`), &result)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), MustParseAmount("19092587.45"), result.IDR())
+	assert.Equal(suite.T(), MustParseAmount("3987.31"), result.USD())
+}
+
 func TestAccountsTestSuite(t *testing.T) {
 	suite.Run(t, new(AccountsTestSuite))
 }
@@ -108,7 +140,7 @@ func (suite *AccountsResourceTestSuite) TestGetAccountsSuccess() {
 	body, _ := LoadStubResponseData("stubs/accounts/details/success.xml")
 	httpmock.RegisterResponder(http.MethodPost, suite.cfg.Uri, httpmock.NewBytesResponder(http.StatusOK, body))
 
-	accounts := []string{"FP0000001"}
+	accounts := []AccountNumber{"FP0000001"}
 	result, resp, err := suite.testable.GetAccounts(accounts, suite.ctx, nil)
 	assert.NoError(suite.T(), err)
 	assert.NotEmpty(suite.T(), resp)
@@ -119,11 +151,11 @@ func (suite *AccountsResourceTestSuite) TestGetAccountsSuccess() {
 	assert.Equal(suite.T(), "2013-01-01T10:58:43+07:00", result.DateTime)
 	//accounts
 	assert.Equal(suite.T(), "Budiman", result.Accounts[0].FullName)
-	assert.Equal(suite.T(), "FP00001", result.Accounts[0].Account)
+	assert.Equal(suite.T(), AccountNumber("FP00001"), result.Accounts[0].Account)
 	assert.Equal(suite.T(), "Store", result.Accounts[0].Status)
 
 	assert.Equal(suite.T(), "Ani Permata", result.Accounts[1].FullName)
-	assert.Equal(suite.T(), "FP00002", result.Accounts[1].Account)
+	assert.Equal(suite.T(), AccountNumber("FP00002"), result.Accounts[1].Account)
 	assert.Equal(suite.T(), "Verified", result.Accounts[1].Status)
 	//response
 	defer resp.Body.Close()
@@ -135,7 +167,7 @@ func (suite *AccountsResourceTestSuite) TestGetAccountsXmlError() {
 	body, _ := LoadStubResponseData("stubs/accounts/details/error.xml")
 	httpmock.RegisterResponder(http.MethodPost, suite.cfg.Uri, httpmock.NewBytesResponder(http.StatusOK, body))
 
-	accounts := []string{"FP0000001"}
+	accounts := []AccountNumber{"FP0000001"}
 	result, resp, err := suite.testable.GetAccounts(accounts, suite.ctx, nil)
 	assert.Error(suite.T(), err)
 	assert.NotEmpty(suite.T(), resp)
@@ -158,14 +190,22 @@ func (suite *AccountsResourceTestSuite) TestGetAccountsXmlError() {
 	bodyRsp, _ := ioutil.ReadAll(resp.Body)
 	assert.Equal(suite.T(), body, bodyRsp)
 	//error
-	assert.Equal(suite.T(), "UNEXPECTED ERROR", err.Error())
+	assert.Equal(suite.T(), ErrorMessageAccountNotFound, err.Error())
+	assert.True(suite.T(), errors.Is(err, ErrAccountNotFound))
+	assert.True(suite.T(), errors.Is(err, ErrAccountRequest))
+	var apiErr *APIError
+	assert.True(suite.T(), errors.As(err, &apiErr))
+	assert.Equal(suite.T(), "account", apiErr.Mode)
+	assert.Equal(suite.T(), uint64(41001), apiErr.Code)
+	assert.Equal(suite.T(), "ACCOUNT NOT FOUND", apiErr.Errors[0].Message)
+	assert.True(suite.T(), errors.Is(err, ErrAccountNotFound))
 }
 
 func (suite *AccountsResourceTestSuite) TestGetAccountsNonXmlError() {
 	body, _ := LoadStubResponseData("stubs/errors/500.html")
 	httpmock.RegisterResponder(http.MethodPost, suite.cfg.Uri, httpmock.NewBytesResponder(http.StatusOK, body))
 
-	accounts := []string{"FP0000001"}
+	accounts := []AccountNumber{"FP0000001"}
 	result, resp, err := suite.testable.GetAccounts(accounts, suite.ctx, nil)
 
 	assert.Error(suite.T(), err)
@@ -176,7 +216,7 @@ func (suite *AccountsResourceTestSuite) TestGetAccountsNonXmlError() {
 	bodyRsp, _ := ioutil.ReadAll(resp.Body)
 	assert.Equal(suite.T(), body, bodyRsp)
 	//error
-	assert.Equal(suite.T(), "AccountsResource.GetAccounts error: EOF", err.Error())
+	assert.Equal(suite.T(), `AccountsResource.GetAccounts error: unexpected HTTP response: status 200, content type "", body "Bad request"`, err.Error())
 }
 
 func (suite *AccountsResourceTestSuite) TestGetBalancesSuccess() {
@@ -193,14 +233,83 @@ func (suite *AccountsResourceTestSuite) TestGetBalancesSuccess() {
 	assert.Equal(suite.T(), "1234567", result.Id)
 	assert.Equal(suite.T(), "2013-01-01T10:58:43+07:00", result.DateTime)
 	//balances
-	assert.Equal(suite.T(), 19092587.45, result.Balances.IDR)
-	assert.Equal(suite.T(), 3987.31, result.Balances.USD)
+	assert.Equal(suite.T(), MustParseAmount("19092587.45"), result.Balances.IDR())
+	assert.Equal(suite.T(), MustParseAmount("3987.31"), result.Balances.USD())
 	//response
 	defer resp.Body.Close()
 	bodyRsp, _ := ioutil.ReadAll(resp.Body)
 	assert.Equal(suite.T(), body, bodyRsp)
 }
 
+func (suite *AccountsResourceTestSuite) TestGetBalancesMissingBalance() {
+	var params *GetBalancesResponseParams
+	amount, ok := params.Get(CurrencyCodeIDR)
+	assert.False(suite.T(), ok)
+	assert.True(suite.T(), amount.IsZero())
+	assert.True(suite.T(), params.IDR().IsZero())
+	assert.True(suite.T(), params.USD().IsZero())
+	assert.Equal(suite.T(), []CurrencyCode{}, params.Currencies())
+
+	body := []byte(`<fasa_response id="1" date_time="2013-01-01T10:58:43+07:00"></fasa_response>`)
+	httpmock.RegisterResponder(http.MethodPost, suite.cfg.Uri, httpmock.NewBytesResponder(http.StatusOK, body))
+	result, _, err := suite.testable.GetBalances([]CurrencyCode{CurrencyCodeIDR}, suite.ctx, nil)
+	assert.NoError(suite.T(), err)
+	assert.Nil(suite.T(), result.Balances)
+	assert.True(suite.T(), result.Balances.IDR().IsZero())
+	assert.Empty(suite.T(), result.Balances.Currencies())
+}
+
+func (suite *AccountsResourceTestSuite) TestGetBalancesRetrySuccess() {
+	body, _ := LoadStubResponseData("stubs/accounts/balances/success.xml")
+	calls := 0
+	httpmock.RegisterResponder(http.MethodPost, suite.cfg.Uri, func(req *http.Request) (*http.Response, error) {
+		calls++
+		if calls == 1 {
+			return httpmock.NewStringResponse(http.StatusServiceUnavailable, "Service unavailable"), nil
+		}
+		return httpmock.NewBytesResponse(http.StatusOK, body), nil
+	})
+	suite.testable.tr.retry = &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}
+
+	result, resp, err := suite.testable.GetBalances([]CurrencyCode{CurrencyCodeIDR, CurrencyCodeUSD}, suite.ctx, nil)
+	assert.NoError(suite.T(), err)
+	assert.NotEmpty(suite.T(), resp)
+	assert.True(suite.T(), result.IsSuccess())
+	assert.Equal(suite.T(), 2, calls)
+}
+
+func (suite *AccountsResourceTestSuite) TestGetBalancesNormalizeCurrencies() {
+	body, _ := LoadStubResponseData("stubs/accounts/balances/success.xml")
+	var requestBody string
+	httpmock.RegisterResponder(http.MethodPost, suite.cfg.Uri, func(req *http.Request) (*http.Response, error) {
+		bts, _ := ioutil.ReadAll(req.Body)
+		requestBody = string(bts)
+		return httpmock.NewBytesResponse(http.StatusOK, body), nil
+	})
+
+	_, _, err := suite.testable.GetBalances([]CurrencyCode{"idr", " usd"}, suite.ctx, nil)
+	assert.NoError(suite.T(), err)
+	assert.Contains(suite.T(), requestBody, "<balance>IDR</balance><balance>USD</balance>")
+}
+
+func (suite *AccountsResourceTestSuite) TestGetAccountsInvalidAccountNumber() {
+	result, resp, err := suite.testable.GetAccounts([]AccountNumber{"FP0000001", "12345"}, suite.ctx, nil)
+	assert.Error(suite.T(), err)
+	assert.Nil(suite.T(), resp)
+	assert.Nil(suite.T(), result)
+	assert.Equal(suite.T(), `AccountsResource.GetAccounts error: account number "12345" is not valid, FPnnnnn expected`, err.Error())
+	assert.Equal(suite.T(), 0, httpmock.GetTotalCallCount())
+}
+
+func (suite *AccountsResourceTestSuite) TestGetBalancesUnsupportedCurrency() {
+	result, resp, err := suite.testable.GetBalances([]CurrencyCode{CurrencyCodeIDR, "CHY"}, suite.ctx, nil)
+	assert.Error(suite.T(), err)
+	assert.Nil(suite.T(), resp)
+	assert.Nil(suite.T(), result)
+	assert.Equal(suite.T(), `AccountsResource.GetBalances error: currency "CHY" is not supported`, err.Error())
+	assert.Equal(suite.T(), 0, httpmock.GetTotalCallCount())
+}
+
 func (suite *AccountsResourceTestSuite) TestGetBalancesXmlError() {
 	body, _ := LoadStubResponseData("stubs/accounts/balances/error.xml")
 	httpmock.RegisterResponder(http.MethodPost, suite.cfg.Uri, httpmock.NewBytesResponder(http.StatusOK, body))
@@ -228,7 +337,10 @@ func (suite *AccountsResourceTestSuite) TestGetBalancesXmlError() {
 	bodyRsp, _ := ioutil.ReadAll(resp.Body)
 	assert.Equal(suite.T(), body, bodyRsp)
 	//error
-	assert.Equal(suite.T(), "UNEXPECTED ERROR", err.Error())
+	assert.Equal(suite.T(), ErrorMessageWrongOrInactiveCurrency, err.Error())
+	assert.True(suite.T(), errors.Is(err, ErrWrongOrInactiveCurrency))
+	assert.True(suite.T(), errors.Is(err, ErrBalanceRequest))
+	assert.False(suite.T(), errors.Is(err, ErrAccountRequest))
 }
 
 func (suite *AccountsResourceTestSuite) TestGetBalancesNonXmlError() {
@@ -246,7 +358,7 @@ func (suite *AccountsResourceTestSuite) TestGetBalancesNonXmlError() {
 	bodyRsp, _ := ioutil.ReadAll(resp.Body)
 	assert.Equal(suite.T(), body, bodyRsp)
 	//error
-	assert.Equal(suite.T(), "AccountsResource.GetBalances error: EOF", err.Error())
+	assert.Equal(suite.T(), `AccountsResource.GetBalances error: unexpected HTTP response: status 200, content type "", body "Bad request"`, err.Error())
 }
 
 func TestAccountsResourceTestSuite(t *testing.T) {
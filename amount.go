@@ -0,0 +1,282 @@
+package fasapay
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+//MaxAmountScale max supported number of decimal places
+const MaxAmountScale uint8 = 8
+
+//pow10 powers of ten up to MaxAmountScale
+var pow10 = [...]int64{1, 10, 100, 1000, 10000, 100000, 1000000, 10000000, 100000000}
+
+//Amount struct - exact decimal money amount, backed by integer units and number of decimal places (value * 10^-scale).
+//The number of decimal places is preserved, so "1000.000" is marshalled back as "1000.000".
+//Arithmetic methods panic if the result does not fit into int64 units instead of wrapping around.
+type Amount struct {
+	value int64
+	scale uint8
+}
+
+//NewAmount Create new amount from integer units and number of decimal places (NewAmount(1050, 2) is 10.50)
+func NewAmount(value int64, scale uint8) Amount {
+	if scale > MaxAmountScale {
+		panic(fmt.Sprintf("fasapay: amount scale %d exceeds max scale %d", scale, MaxAmountScale))
+	}
+	return Amount{value: value, scale: scale}
+}
+
+//ParseAmount Parse amount from decimal string with point (.) as the decimal separator
+func ParseAmount(str string) (Amount, error) {
+	s := strings.TrimSpace(str)
+	negative := false
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		negative = s[0] == '-'
+		s = s[1:]
+	}
+	integer, fraction := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		integer, fraction = s[:i], s[i+1:]
+	}
+	if (integer == "" && fraction == "") || !isDigits(integer) || !isDigits(fraction) {
+		return Amount{}, fmt.Errorf(`invalid amount "%s"`, str)
+	}
+	if len(fraction) > int(MaxAmountScale) {
+		return Amount{}, fmt.Errorf(`amount "%s" has more than %d decimal places`, str, MaxAmountScale)
+	}
+	value, err := strconv.ParseInt(integer+fraction, 10, 64)
+	if err != nil {
+		return Amount{}, fmt.Errorf(`amount "%s" is out of range`, str)
+	}
+	if negative {
+		value = -value
+	}
+	return Amount{value: value, scale: uint8(len(fraction))}, nil
+}
+
+//MustParseAmount Parse amount from decimal string or panic
+func MustParseAmount(str string) Amount {
+	amount, err := ParseAmount(str)
+	if err != nil {
+		panic(err)
+	}
+	return amount
+}
+
+//isDigits check is string contains only decimal digits
+func isDigits(str string) bool {
+	for _, r := range str {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+//Value method - integer units (minor units for scale equal to the currency precision)
+func (a Amount) Value() int64 {
+	return a.value
+}
+
+//Scale method - number of decimal places
+func (a Amount) Scale() uint8 {
+	return a.scale
+}
+
+//Decimals method - number of significant decimal places (trailing zeros are ignored)
+func (a Amount) Decimals() uint8 {
+	scale := a.scale
+	value := a.value
+	for scale > 0 && value%10 == 0 {
+		value /= 10
+		scale--
+	}
+	return scale
+}
+
+//String method
+func (a Amount) String() string {
+	str := strconv.FormatInt(a.value, 10)
+	if a.scale == 0 {
+		return str
+	}
+	sign := ""
+	if a.value < 0 {
+		sign, str = "-", str[1:]
+	}
+	if len(str) <= int(a.scale) {
+		str = strings.Repeat("0", int(a.scale)-len(str)+1) + str
+	}
+	i := len(str) - int(a.scale)
+	return sign + str[:i] + "." + str[i:]
+}
+
+//Float64 method - approximate float value (for displaying only)
+func (a Amount) Float64() float64 {
+	return float64(a.value) / math.Pow10(int(a.scale))
+}
+
+//Sign method - -1 if amount is negative, 0 if zero, +1 if positive
+func (a Amount) Sign() int {
+	switch {
+	case a.value < 0:
+		return -1
+	case a.value > 0:
+		return 1
+	}
+	return 0
+}
+
+//IsZero method
+func (a Amount) IsZero() bool {
+	return a.value == 0
+}
+
+//IsPositive method
+func (a Amount) IsPositive() bool {
+	return a.value > 0
+}
+
+//IsNegative method
+func (a Amount) IsNegative() bool {
+	return a.value < 0
+}
+
+//Cmp method - compare amounts: -1 if a < b, 0 if a == b, +1 if a > b
+func (a Amount) Cmp(b Amount) int {
+	x, y := alignBigAmounts(a, b)
+	return x.Cmp(y)
+}
+
+//Equal method - compare amounts values regardless of number of decimal places
+func (a Amount) Equal(b Amount) bool {
+	return a.Cmp(b) == 0
+}
+
+//Add method
+func (a Amount) Add(b Amount) Amount {
+	x, y := alignBigAmounts(a, b)
+	return newAmountFromBig(x.Add(x, y), maxScale(a, b))
+}
+
+//Sub method
+func (a Amount) Sub(b Amount) Amount {
+	x, y := alignBigAmounts(a, b)
+	return newAmountFromBig(x.Sub(x, y), maxScale(a, b))
+}
+
+//Neg method
+func (a Amount) Neg() Amount {
+	return newAmountFromBig(new(big.Int).Neg(big.NewInt(a.value)), a.scale)
+}
+
+//MulInt method - multiply amount by integer
+func (a Amount) MulInt(n int64) Amount {
+	return newAmountFromBig(new(big.Int).Mul(big.NewInt(a.value), big.NewInt(n)), a.scale)
+}
+
+//Percent method - calculate percent of amount, rounded half up to scale decimal places (max MaxAmountScale)
+func (a Amount) Percent(rate Amount, scale uint8) Amount {
+	if scale > MaxAmountScale {
+		scale = MaxAmountScale
+	}
+	//a * rate / 100 = (a.value * rate.value) / 10^(a.scale + rate.scale + 2)
+	numerator := new(big.Int).Mul(big.NewInt(a.value), big.NewInt(rate.value))
+	numerator.Mul(numerator, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(scale)), nil))
+	denominator := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(a.scale)+int64(rate.scale)+2), nil)
+	return newAmountFromBig(divRoundHalfUp(numerator, denominator), scale)
+}
+
+//Rescale method - change number of decimal places, rounding half up if needed
+func (a Amount) Rescale(scale uint8) Amount {
+	if scale > MaxAmountScale {
+		scale = MaxAmountScale
+	}
+	if scale >= a.scale {
+		return newAmountFromBig(new(big.Int).Mul(big.NewInt(a.value), big.NewInt(pow10[scale-a.scale])), scale)
+	}
+	value := divRoundHalfUp(big.NewInt(a.value), big.NewInt(pow10[a.scale-scale]))
+	return newAmountFromBig(value, scale)
+}
+
+//MarshalText method implementation
+func (a Amount) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+//UnmarshalText method implementation (empty value is zero amount)
+func (a *Amount) UnmarshalText(text []byte) error {
+	if len(bytes.TrimSpace(text)) == 0 {
+		*a = Amount{}
+		return nil
+	}
+	amount, err := ParseAmount(string(text))
+	if err != nil {
+		return err
+	}
+	*a = amount
+	return nil
+}
+
+//MarshalJSON method implementation (json number with preserved decimal places)
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+//UnmarshalJSON method implementation (json number or string)
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var str string
+		if err := json.Unmarshal(data, &str); err != nil {
+			return err
+		}
+		return a.UnmarshalText([]byte(str))
+	}
+	return a.UnmarshalText(data)
+}
+
+//newAmountFromBig Create new amount from big integer units or panic if units are out of int64 range
+func newAmountFromBig(value *big.Int, scale uint8) Amount {
+	if !value.IsInt64() {
+		panic(fmt.Sprintf("fasapay: amount overflow, %s units with scale %d are out of range", value.String(), scale))
+	}
+	return Amount{value: value.Int64(), scale: scale}
+}
+
+//maxScale max number of decimal places of amounts
+func maxScale(a Amount, b Amount) uint8 {
+	if a.scale > b.scale {
+		return a.scale
+	}
+	return b.scale
+}
+
+//alignBigAmounts big integer units of amounts with the same number of decimal places
+func alignBigAmounts(a Amount, b Amount) (*big.Int, *big.Int) {
+	scale := maxScale(a, b)
+	x := new(big.Int).Mul(big.NewInt(a.value), big.NewInt(pow10[scale-a.scale]))
+	y := new(big.Int).Mul(big.NewInt(b.value), big.NewInt(pow10[scale-b.scale]))
+	return x, y
+}
+
+//divRoundHalfUp integer division rounded half away from zero
+func divRoundHalfUp(numerator *big.Int, denominator *big.Int) *big.Int {
+	quotient, remainder := new(big.Int).QuoRem(numerator, denominator, new(big.Int))
+	if new(big.Int).Mul(new(big.Int).Abs(remainder), big.NewInt(2)).Cmp(denominator) >= 0 {
+		if numerator.Sign() < 0 {
+			quotient.Sub(quotient, big.NewInt(1))
+		} else {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+	}
+	return quotient
+}
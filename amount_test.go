@@ -0,0 +1,175 @@
+package fasapay
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"math"
+	"testing"
+)
+
+type AmountTestSuite struct {
+	suite.Suite
+}
+
+func (suite *AmountTestSuite) TestParseAmountSuccess() {
+	cases := map[string]Amount{
+		"1000.000":    {value: 1000000, scale: 3},
+		"1000.0":      {value: 10000, scale: 1},
+		"100":         {value: 100, scale: 0},
+		"19092587.45": {value: 1909258745, scale: 2},
+		" 0.05 ":      {value: 5, scale: 2},
+		".5":          {value: 5, scale: 1},
+		"5.":          {value: 5, scale: 0},
+		"-10.50":      {value: -1050, scale: 2},
+		"+1":          {value: 1, scale: 0},
+	}
+	for str, expected := range cases {
+		result, err := ParseAmount(str)
+		assert.NoError(suite.T(), err, str)
+		assert.Equal(suite.T(), expected, result, str)
+	}
+}
+
+func (suite *AmountTestSuite) TestParseAmountError() {
+	for _, str := range []string{"", ".", "-", "1,5", "1.2.3", "abc", "1e3", "0.123456789", "99999999999999999999"} {
+		_, err := ParseAmount(str)
+		assert.Error(suite.T(), err, str)
+	}
+	_, err := ParseAmount("foo")
+	assert.Equal(suite.T(), `invalid amount "foo"`, err.Error())
+}
+
+func (suite *AmountTestSuite) TestMustParseAmount() {
+	assert.Equal(suite.T(), NewAmount(105, 1), MustParseAmount("10.5"))
+	assert.Panics(suite.T(), func() { MustParseAmount("foo") })
+}
+
+func (suite *AmountTestSuite) TestNewAmount() {
+	result := NewAmount(1050, 2)
+	assert.Equal(suite.T(), int64(1050), result.Value())
+	assert.Equal(suite.T(), uint8(2), result.Scale())
+	assert.Equal(suite.T(), "10.50", result.String())
+	assert.Panics(suite.T(), func() { NewAmount(1, MaxAmountScale+1) })
+}
+
+func (suite *AmountTestSuite) TestString() {
+	assert.Equal(suite.T(), "1000.000", MustParseAmount("1000.000").String())
+	assert.Equal(suite.T(), "0.05", MustParseAmount("0.05").String())
+	assert.Equal(suite.T(), "-0.05", MustParseAmount("-0.05").String())
+	assert.Equal(suite.T(), "0.5", MustParseAmount(".5").String())
+	assert.Equal(suite.T(), "0", Amount{}.String())
+}
+
+func (suite *AmountTestSuite) TestDecimals() {
+	assert.Equal(suite.T(), uint8(0), MustParseAmount("1000.000").Decimals())
+	assert.Equal(suite.T(), uint8(2), MustParseAmount("10.050").Decimals())
+	assert.Equal(suite.T(), uint8(0), Amount{}.Decimals())
+}
+
+func (suite *AmountTestSuite) TestFloat64() {
+	assert.Equal(suite.T(), 19092587.45, MustParseAmount("19092587.45").Float64())
+}
+
+func (suite *AmountTestSuite) TestSign() {
+	assert.Equal(suite.T(), 1, MustParseAmount("0.01").Sign())
+	assert.Equal(suite.T(), -1, MustParseAmount("-0.01").Sign())
+	assert.Equal(suite.T(), 0, MustParseAmount("0.00").Sign())
+	assert.True(suite.T(), MustParseAmount("0.00").IsZero())
+	assert.True(suite.T(), MustParseAmount("1").IsPositive())
+	assert.True(suite.T(), MustParseAmount("-1").IsNegative())
+}
+
+func (suite *AmountTestSuite) TestCmp() {
+	assert.Equal(suite.T(), 0, MustParseAmount("1000.000").Cmp(MustParseAmount("1000")))
+	assert.Equal(suite.T(), -1, MustParseAmount("999.99").Cmp(MustParseAmount("1000")))
+	assert.Equal(suite.T(), 1, MustParseAmount("1000.01").Cmp(MustParseAmount("1000")))
+	assert.True(suite.T(), MustParseAmount("1100").Equal(MustParseAmount("1100.0")))
+}
+
+func (suite *AmountTestSuite) TestArithmetic() {
+	assert.Equal(suite.T(), "1100.000", MustParseAmount("1000.000").Add(MustParseAmount("100")).String())
+	assert.Equal(suite.T(), "900.5", MustParseAmount("1000").Sub(MustParseAmount("99.5")).String())
+	assert.Equal(suite.T(), "-0.10", MustParseAmount("0.10").Neg().String())
+	assert.Equal(suite.T(), "30.30", MustParseAmount("10.10").MulInt(3).String())
+	assert.Equal(suite.T(), "0.3", MustParseAmount("0.1").Add(MustParseAmount("0.2")).String())
+}
+
+func (suite *AmountTestSuite) TestArithmeticOverflow() {
+	large := MustParseAmount("100000000000")
+	assert.Panics(suite.T(), func() { large.Add(MustParseAmount("0.00000001")) })
+	assert.Panics(suite.T(), func() { large.Neg().Sub(MustParseAmount("0.00000001")) })
+	assert.Panics(suite.T(), func() { NewAmount(math.MaxInt64, 0).MulInt(2) })
+	assert.Panics(suite.T(), func() { NewAmount(math.MinInt64, 0).Neg() })
+	assert.Panics(suite.T(), func() { large.Rescale(MaxAmountScale) })
+	assert.Panics(suite.T(), func() { NewAmount(math.MaxInt64, 0).Percent(MustParseAmount("200"), 0) })
+	assert.Equal(suite.T(), "9223372036854775807", NewAmount(math.MaxInt64-1, 0).Add(MustParseAmount("1")).String())
+}
+
+func (suite *AmountTestSuite) TestCmpLarge() {
+	large := MustParseAmount("100000000000")
+	assert.Equal(suite.T(), 1, large.Cmp(MustParseAmount("0.00000001")))
+	assert.Equal(suite.T(), -1, large.Neg().Cmp(MustParseAmount("0.00000001")))
+	assert.True(suite.T(), large.Equal(MustParseAmount("100000000000.0")))
+}
+
+func (suite *AmountTestSuite) TestPercent() {
+	assert.Equal(suite.T(), "5.00", MustParseAmount("1000").Percent(MustParseAmount("0.5"), 2).String())
+	assert.Equal(suite.T(), "0.01", MustParseAmount("1.00").Percent(MustParseAmount("0.5"), 2).String())
+	assert.Equal(suite.T(), "0.00", MustParseAmount("0.99").Percent(MustParseAmount("0.5"), 2).String())
+	assert.Equal(suite.T(), "-0.01", MustParseAmount("-1.00").Percent(MustParseAmount("0.5"), 2).String())
+	result := MustParseAmount("1.23456789").Percent(MustParseAmount("100"), MaxAmountScale+1)
+	assert.Equal(suite.T(), MaxAmountScale, result.Scale())
+	assert.Equal(suite.T(), "1.23456789", result.String())
+	assert.True(suite.T(), result.Add(MustParseAmount("1")).Equal(MustParseAmount("2.23456789")))
+}
+
+func (suite *AmountTestSuite) TestRescale() {
+	assert.Equal(suite.T(), "1000.00", MustParseAmount("1000").Rescale(2).String())
+	assert.Equal(suite.T(), "10.01", MustParseAmount("10.005").Rescale(2).String())
+	assert.Equal(suite.T(), "10.00", MustParseAmount("10.004").Rescale(2).String())
+	assert.Equal(suite.T(), "-10.01", MustParseAmount("-10.005").Rescale(2).String())
+}
+
+func (suite *AmountTestSuite) TestMarshalXml() {
+	type item struct {
+		XMLName xml.Name `xml:"item"`
+		Amount  Amount   `xml:"amount"`
+	}
+	bytes, err := xml.Marshal(&item{Amount: MustParseAmount("1000.000")})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), `<item><amount>1000.000</amount></item>`, string(bytes))
+
+	var result item
+	err = xml.Unmarshal([]byte(`<item><amount>19092587.45</amount></item>`), &result)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), MustParseAmount("19092587.45"), result.Amount)
+
+	err = xml.Unmarshal([]byte(`<item><amount></amount></item>`), &result)
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), result.Amount.IsZero())
+
+	err = xml.Unmarshal([]byte(`<item><amount>foo</amount></item>`), &result)
+	assert.Error(suite.T(), err)
+}
+
+func (suite *AmountTestSuite) TestMarshalJson() {
+	bytes, err := json.Marshal(map[string]Amount{"amount": MustParseAmount("1000.000")})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), `{"amount":1000.000}`, string(bytes))
+
+	var result map[string]Amount
+	err = json.Unmarshal([]byte(`{"a":10.50,"b":"0.05","c":null}`), &result)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), MustParseAmount("10.50"), result["a"])
+	assert.Equal(suite.T(), MustParseAmount("0.05"), result["b"])
+	assert.True(suite.T(), result["c"].IsZero())
+
+	err = json.Unmarshal([]byte(`{"a":"foo"}`), &result)
+	assert.Error(suite.T(), err)
+}
+
+func TestAmountTestSuite(t *testing.T) {
+	suite.Run(t, new(AmountTestSuite))
+}
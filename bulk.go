@@ -0,0 +1,212 @@
+package fasapay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+//DefaultBulkChunkSize default number of transfers submitted in one request
+const DefaultBulkChunkSize = 50
+
+//ErrTransferSkipped transfer is not submitted, because bulk transfer is stopped after failure
+var ErrTransferSkipped = errors.New("transfer is skipped after previous failure")
+
+//BulkFailurePolicy type - bulk transfer behaviour after failed chunk
+type BulkFailurePolicy string
+
+const (
+	//BulkFailurePolicyContinue submit remaining chunks after failure
+	BulkFailurePolicyContinue BulkFailurePolicy = "continue"
+	//BulkFailurePolicyStop do not submit remaining chunks after failure
+	BulkFailurePolicyStop BulkFailurePolicy = "stop"
+)
+
+//BulkTransferOptions struct
+type BulkTransferOptions struct {
+	ChunkSize   int               //max transfers in one request, DefaultBulkChunkSize if not positive
+	Concurrency int               //max concurrently submitted chunks, sequential submission if not positive
+	OnFailure   BulkFailurePolicy //behaviour after failed chunk, continue by default
+}
+
+//NewBulkTransferOptions Create new bulk transfer options with default parameters
+func NewBulkTransferOptions() *BulkTransferOptions {
+	return &BulkTransferOptions{ChunkSize: DefaultBulkChunkSize, Concurrency: 1, OnFailure: BulkFailurePolicyContinue}
+}
+
+//chunkSize method
+func (o *BulkTransferOptions) chunkSize() int {
+	if o == nil || o.ChunkSize < 1 {
+		return DefaultBulkChunkSize
+	}
+	return o.ChunkSize
+}
+
+//concurrency method
+func (o *BulkTransferOptions) concurrency() int {
+	if o == nil || o.Concurrency < 1 {
+		return 1
+	}
+	return o.Concurrency
+}
+
+//stopOnFailure method
+func (o *BulkTransferOptions) stopOnFailure() bool {
+	return o != nil && o.OnFailure == BulkFailurePolicyStop
+}
+
+//BulkTransferResult struct - outcome of the single transfer of bulk transfer
+type BulkTransferResult struct {
+	Index    int                           //index of the transfer in the bulk
+	Transfer *CreateTransferRequestParams  //submitted transfer
+	Result   *CreateTransferResponseParams //accepted transfer, nil on failure
+	Err      error                         //failure of the transfer
+}
+
+//IsSuccess method
+func (r *BulkTransferResult) IsSuccess() bool {
+	return r.Err == nil && r.Result != nil
+}
+
+//BulkTransferReport struct - outcomes of all transfers of bulk transfer in submission order
+type BulkTransferReport struct {
+	Results   []*BulkTransferResult
+	Succeeded int
+	Failed    int
+	Skipped   int
+}
+
+//IsSuccess method - are all transfers accepted
+func (r *BulkTransferReport) IsSuccess() bool {
+	return r.Failed == 0 && r.Skipped == 0
+}
+
+//Successful method - accepted transfers
+func (r *BulkTransferReport) Successful() []*BulkTransferResult {
+	var result []*BulkTransferResult
+	for _, item := range r.Results {
+		if item.IsSuccess() {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+//Failures method - failed and skipped transfers
+func (r *BulkTransferReport) Failures() []*BulkTransferResult {
+	var result []*BulkTransferResult
+	for _, item := range r.Results {
+		if !item.IsSuccess() {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+//count method - count outcomes
+func (r *BulkTransferReport) count() {
+	for _, item := range r.Results {
+		switch {
+		case item.IsSuccess():
+			r.Succeeded++
+		case errors.Is(item.Err, ErrTransferSkipped):
+			r.Skipped++
+		default:
+			r.Failed++
+		}
+	}
+}
+
+//BulkTransfer method - submit any number of transfers split into API-sized chunks.
+//All transfers are validated before the first submission. Chunks are submitted with bounded concurrency,
+//after failed chunk the remaining ones are submitted or skipped according to the failure policy.
+//Every chunk is sent with its own request id: the id of attributes with chunk index suffix (e.g. "1234567-0").
+func (r *TransfersResource) BulkTransfer(transfers []*CreateTransferRequestParams, ctx context.Context, options *BulkTransferOptions, attributes *RequestParamsAttributes) (*BulkTransferReport, error) {
+	err := r.validateTransferParams(normalizeTransfers(transfers))
+	if err != nil {
+		return nil, fmt.Errorf("TransfersResource.BulkTransfer error: %w", err)
+	}
+	if attributes == nil {
+		attributes = newRequestParamsAttributes()
+	}
+	report := &BulkTransferReport{Results: make([]*BulkTransferResult, len(transfers))}
+	for index, transfer := range transfers {
+		report.Results[index] = &BulkTransferResult{Index: index, Transfer: transfer}
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	stopped := false
+	semaphore := make(chan struct{}, options.concurrency())
+	size := options.chunkSize()
+	for start := 0; start < len(transfers); start += size {
+		end := start + size
+		if end > len(transfers) {
+			end = len(transfers)
+		}
+		chunk := report.Results[start:end]
+		chunkAttributes := bulkChunkAttributes(attributes, start/size)
+
+		semaphore <- struct{}{}
+		mu.Lock()
+		skip := stopped
+		mu.Unlock()
+		if err := ctx.Err(); skip || err != nil {
+			<-semaphore
+			if err == nil {
+				err = ErrTransferSkipped
+			}
+			failBulkChunk(chunk, err)
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			if !r.submitBulkChunk(chunk, ctx, chunkAttributes) && options.stopOnFailure() {
+				mu.Lock()
+				stopped = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	report.count()
+	return report, nil
+}
+
+//bulkChunkAttributes Build request attributes of the chunk with its own request id
+func bulkChunkAttributes(attributes *RequestParamsAttributes, chunk int) *RequestParamsAttributes {
+	return &RequestParamsAttributes{Id: fmt.Sprintf("%s-%d", attributes.Id, chunk), DateTime: attributes.DateTime}
+}
+
+//submitBulkChunk method - submit chunk of bulk transfer, false on failure of any transfer
+func (r *TransfersResource) submitBulkChunk(chunk []*BulkTransferResult, ctx context.Context, attributes *RequestParamsAttributes) bool {
+	transfers := make([]*CreateTransferRequestParams, len(chunk))
+	for i, item := range chunk {
+		transfers[i] = item.Transfer
+	}
+	result, _, err := r.CreateTransfer(transfers, ctx, attributes)
+	if result == nil || len(result.Results) != len(chunk) {
+		if err == nil {
+			err = fmt.Errorf("transfer results are missing in response")
+		}
+		failBulkChunk(chunk, err)
+		return false
+	}
+	success := true
+	for i, item := range chunk {
+		item.Result = result.Results[i].Result
+		item.Err = result.Results[i].Err()
+		success = success && item.Err == nil
+	}
+	return success
+}
+
+//failBulkChunk Set failure of all chunk transfers
+func failBulkChunk(chunk []*BulkTransferResult, err error) {
+	for _, item := range chunk {
+		item.Err = err
+	}
+}
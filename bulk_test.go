@@ -0,0 +1,214 @@
+package fasapay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type BulkTransferOptionsTestSuite struct {
+	suite.Suite
+}
+
+func (suite *BulkTransferOptionsTestSuite) TestDefaults() {
+	options := NewBulkTransferOptions()
+	assert.Equal(suite.T(), DefaultBulkChunkSize, options.chunkSize())
+	assert.Equal(suite.T(), 1, options.concurrency())
+	assert.False(suite.T(), options.stopOnFailure())
+
+	var empty *BulkTransferOptions
+	assert.Equal(suite.T(), DefaultBulkChunkSize, empty.chunkSize())
+	assert.Equal(suite.T(), 1, empty.concurrency())
+	assert.False(suite.T(), empty.stopOnFailure())
+}
+
+func (suite *BulkTransferOptionsTestSuite) TestStopOnFailure() {
+	options := &BulkTransferOptions{OnFailure: BulkFailurePolicyStop}
+	assert.True(suite.T(), options.stopOnFailure())
+}
+
+func TestBulkTransferOptionsTestSuite(t *testing.T) {
+	suite.Run(t, new(BulkTransferOptionsTestSuite))
+}
+
+var bulkRequestIdRegexp = regexp.MustCompile(`<fasa_request id="([^"]*)"`)
+
+var bulkRecipientRegexp = regexp.MustCompile(`<to>([^<]*)</to>`)
+
+type BulkTransferTestSuite struct {
+	suite.Suite
+	cfg      *Config
+	ctx      context.Context
+	testable *TransfersResource
+	mu       sync.Mutex
+	requests []int
+	ids      []string
+}
+
+func (suite *BulkTransferTestSuite) SetupTest() {
+	cfg := BuildStubConfig()
+	transport := BuildStubHttpTransport()
+	suite.cfg = cfg
+	suite.ctx = context.Background()
+	suite.testable = &TransfersResource{ResourceAbstract: NewResourceAbstract(transport, cfg)}
+	suite.mu.Lock()
+	suite.requests = nil
+	suite.ids = nil
+	suite.mu.Unlock()
+	httpmock.Activate()
+}
+
+func (suite *BulkTransferTestSuite) TearDownTest() {
+	httpmock.DeactivateAndReset()
+}
+
+//registerResponder method - respond with one accepted transfer per requested one (echoing its recipient), fail requests containing failed account
+func (suite *BulkTransferTestSuite) registerResponder(failed AccountNumber) {
+	errorBody, _ := LoadStubResponseData("stubs/transfers/transfer/error.xml")
+	httpmock.RegisterResponder(http.MethodPost, suite.cfg.Uri, func(req *http.Request) (*http.Response, error) {
+		body, _ := ioutil.ReadAll(req.Body)
+		count := strings.Count(string(body), "<transfer ")
+		suite.mu.Lock()
+		suite.requests = append(suite.requests, count)
+		suite.ids = append(suite.ids, bulkRequestIdRegexp.FindStringSubmatch(string(body))[1])
+		suite.mu.Unlock()
+		if failed != "" && strings.Contains(string(body), "<to>"+string(failed)+"</to>") {
+			return httpmock.NewBytesResponse(http.StatusOK, errorBody), nil
+		}
+		var transfers strings.Builder
+		for i, to := range bulkRecipientRegexp.FindAllStringSubmatch(string(body), -1) {
+			fmt.Fprintf(&transfers, `<transfer mode="transfer" code="203"><batchnumber>TR%d</batchnumber><to>%s</to><amount>1000</amount><fee>100</fee><total>1100</total><currency>IDR</currency></transfer>`, i, to[1])
+		}
+		return httpmock.NewStringResponse(http.StatusOK, `<fasa_response id="1" date_time="2011-07-19T14:06:35+07:00">`+transfers.String()+`</fasa_response>`), nil
+	})
+}
+
+//buildTransfers method
+func (suite *BulkTransferTestSuite) buildTransfers(count int) []*CreateTransferRequestParams {
+	transfers := make([]*CreateTransferRequestParams, count)
+	for i := range transfers {
+		transfers[i] = &CreateTransferRequestParams{
+			Id:       fmt.Sprintf("tid%d", i),
+			To:       AccountNumber(fmt.Sprintf("FP%05d", i)),
+			Amount:   MustParseAmount("1000"),
+			Currency: CurrencyCodeIDR,
+		}
+	}
+	return transfers
+}
+
+func (suite *BulkTransferTestSuite) TestBulkTransferSequential() {
+	suite.registerResponder("")
+	transfers := suite.buildTransfers(5)
+	report, err := suite.testable.BulkTransfer(transfers, suite.ctx, &BulkTransferOptions{ChunkSize: 2}, nil)
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), report.IsSuccess())
+	assert.Equal(suite.T(), []int{2, 2, 1}, suite.requests)
+	assert.Equal(suite.T(), 5, report.Succeeded)
+	assert.Len(suite.T(), report.Results, 5)
+	assert.Len(suite.T(), report.Successful(), 5)
+	assert.Empty(suite.T(), report.Failures())
+	for index, item := range report.Results {
+		assert.Equal(suite.T(), index, item.Index)
+		assert.Equal(suite.T(), transfers[index], item.Transfer)
+	}
+	assert.Equal(suite.T(), "TR1", report.Results[3].Result.BatchNumber)
+	assert.Equal(suite.T(), "TR0", report.Results[4].Result.BatchNumber)
+}
+
+func (suite *BulkTransferTestSuite) TestBulkTransferConcurrent() {
+	suite.registerResponder("")
+	report, err := suite.testable.BulkTransfer(suite.buildTransfers(10), suite.ctx, &BulkTransferOptions{ChunkSize: 3, Concurrency: 3}, nil)
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), report.IsSuccess())
+	assert.Equal(suite.T(), 10, report.Succeeded)
+	assert.Len(suite.T(), suite.requests, 4)
+}
+
+func (suite *BulkTransferTestSuite) TestBulkTransferChunkRequestIds() {
+	suite.registerResponder("")
+	attributes := &RequestParamsAttributes{Id: "1234567", DateTime: time.Now().UTC()}
+	_, err := suite.testable.BulkTransfer(suite.buildTransfers(5), suite.ctx, &BulkTransferOptions{ChunkSize: 2}, attributes)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []string{"1234567-0", "1234567-1", "1234567-2"}, suite.ids)
+}
+
+func (suite *BulkTransferTestSuite) TestBulkTransferConcurrentRequestIds() {
+	suite.registerResponder("")
+	_, err := suite.testable.BulkTransfer(suite.buildTransfers(10), suite.ctx, &BulkTransferOptions{ChunkSize: 3, Concurrency: 3}, nil)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), suite.ids, 4)
+	ids := append([]string(nil), suite.ids...)
+	sort.Strings(ids)
+	for i := 1; i < len(ids); i++ {
+		assert.NotEqual(suite.T(), ids[i-1], ids[i])
+	}
+	assert.True(suite.T(), strings.HasSuffix(ids[0], "-0"))
+}
+
+func (suite *BulkTransferTestSuite) TestBulkTransferContinueOnFailure() {
+	suite.registerResponder("FP00002")
+	report, err := suite.testable.BulkTransfer(suite.buildTransfers(5), suite.ctx, &BulkTransferOptions{ChunkSize: 2}, nil)
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), report.IsSuccess())
+	assert.Equal(suite.T(), []int{2, 2, 1}, suite.requests)
+	assert.Equal(suite.T(), 3, report.Succeeded)
+	assert.Equal(suite.T(), 2, report.Failed)
+	assert.Equal(suite.T(), 0, report.Skipped)
+	failures := report.Failures()
+	assert.Equal(suite.T(), 2, failures[0].Index)
+	assert.Equal(suite.T(), ErrTransferNotProcessed, failures[0].Err)
+	assert.Equal(suite.T(), 3, failures[1].Index)
+	assert.True(suite.T(), errors.Is(failures[1].Err, ErrNotAcceptableTransfer))
+	assert.True(suite.T(), errors.Is(failures[1].Err, ErrUnknownRecipient))
+}
+
+func (suite *BulkTransferTestSuite) TestBulkTransferStopOnFailure() {
+	suite.registerResponder("FP00002")
+	report, err := suite.testable.BulkTransfer(suite.buildTransfers(5), suite.ctx, &BulkTransferOptions{ChunkSize: 2, OnFailure: BulkFailurePolicyStop}, nil)
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), report.IsSuccess())
+	assert.Equal(suite.T(), []int{2, 2}, suite.requests)
+	assert.Equal(suite.T(), 2, report.Succeeded)
+	assert.Equal(suite.T(), 2, report.Failed)
+	assert.Equal(suite.T(), 1, report.Skipped)
+	assert.Equal(suite.T(), ErrTransferSkipped, report.Results[4].Err)
+}
+
+func (suite *BulkTransferTestSuite) TestBulkTransferCanceledContext() {
+	suite.registerResponder("")
+	ctx, cancel := context.WithCancel(suite.ctx)
+	cancel()
+	report, err := suite.testable.BulkTransfer(suite.buildTransfers(3), ctx, &BulkTransferOptions{ChunkSize: 2}, nil)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 3, report.Failed)
+	assert.True(suite.T(), errors.Is(report.Results[0].Err, context.Canceled))
+	assert.Equal(suite.T(), 0, httpmock.GetTotalCallCount())
+}
+
+func (suite *BulkTransferTestSuite) TestBulkTransferInvalid() {
+	transfers := suite.buildTransfers(3)
+	transfers[2].To = ""
+	report, err := suite.testable.BulkTransfer(transfers, suite.ctx, nil, nil)
+	assert.Nil(suite.T(), report)
+	assert.Equal(suite.T(), `TransfersResource.BulkTransfer error: parameter "to" is empty`, err.Error())
+	var ve *ValidationError
+	assert.True(suite.T(), errors.As(err, &ve))
+	assert.Equal(suite.T(), 2, ve.Errors[0].Index)
+	assert.Equal(suite.T(), 0, httpmock.GetTotalCallCount())
+}
+
+func TestBulkTransferTestSuite(t *testing.T) {
+	suite.Run(t, new(BulkTransferTestSuite))
+}
@@ -6,6 +6,8 @@ import "net/http"
 type Client struct {
 	transport *Transport
 	config    *Config
+	store     TransferStore
+	dryRun    bool
 }
 
 //NewClientFromConfig Create new client from config
@@ -18,7 +20,7 @@ func NewClientFromConfig(config *Config, cl *http.Client) (*Client, error) {
 		cl = &http.Client{}
 	}
 	transport := NewHttpTransport(config, cl)
-	return &Client{transport, config}, nil
+	return &Client{transport: transport, config: config}, nil
 }
 
 //Accounts resource method
@@ -28,5 +30,54 @@ func (c *Client) Accounts() *AccountsResource {
 
 //Transfers resource method
 func (c *Client) Transfers() *TransfersResource {
-	return &TransfersResource{ResourceAbstract: NewResourceAbstract(c.transport, c.config)}
+	return &TransfersResource{ResourceAbstract: NewResourceAbstract(c.transport, c.config), store: c.store, dryRun: c.dryRun}
+}
+
+//SetRetryPolicy method - set retry policy for idempotent operations (balances, accounts, history, details).
+//CreateTransfer is retried only with transfer store (see SetTransferStore), because blind resubmission may cause double payment.
+func (c *Client) SetRetryPolicy(policy *RetryPolicy) {
+	c.transport.retry = policy
+}
+
+//SetEndpointPool method - set API endpoints pool used for failover
+func (c *Client) SetEndpointPool(pool *EndpointPool) {
+	c.transport.pool = pool
+}
+
+//SetEndpointHook method - set hook reporting which endpoint served each request
+func (c *Client) SetEndpointHook(hook EndpointHook) {
+	c.transport.onEndpoint = hook
+}
+
+//Use method - register middlewares wrapping every API operation (the first one is the outermost)
+func (c *Client) Use(middlewares ...Middleware) {
+	c.transport.middlewares = append(c.transport.middlewares, middlewares...)
+}
+
+//SetLogger method - set logger of every API operation (credentials are masked)
+func (c *Client) SetLogger(logger Logger) {
+	c.transport.logger = logger
+}
+
+//SetMetrics method - set metrics sink of every API operation
+func (c *Client) SetMetrics(sink MetricsSink) {
+	c.transport.metrics = sink
+}
+
+//SetTracer method - set tracer wrapping every API operation into a span
+func (c *Client) SetTracer(tracer Tracer) {
+	c.transport.tracer = tracer
+}
+
+//SetTransferStore method - enable idempotent transfers submission.
+//Transfers without ref get one derived from their id (random if id is empty too) and are saved into the store before submission,
+//on repeated submission the transfers with unknown outcome are looked up by ref and are not paid twice.
+func (c *Client) SetTransferStore(store TransferStore) {
+	c.store = store
+}
+
+//SetDryRun method - enable dry-run of CreateTransfer for all calls (see WithDryRun for single call):
+//transfers are validated, request is built and logged, but is not sent and simulated response is returned
+func (c *Client) SetDryRun(enabled bool) {
+	c.dryRun = enabled
 }
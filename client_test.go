@@ -3,7 +3,9 @@ package fasapay
 import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
+	"net/http"
 	"testing"
+	"time"
 )
 
 type ClientTestSuite struct {
@@ -37,6 +39,71 @@ func (suite *ClientTestSuite) TestGetTransfersResource() {
 	assert.NotEmpty(suite.T(), result)
 }
 
+func (suite *ClientTestSuite) TestSetRetryPolicy() {
+	client, _ := NewClientFromConfig(BuildStubConfig(), nil)
+	policy := NewRetryPolicy()
+	client.SetRetryPolicy(policy)
+	assert.Equal(suite.T(), policy, client.transport.retry)
+}
+
+func (suite *ClientTestSuite) TestSetEndpointPool() {
+	client, _ := NewClientFromConfig(BuildStubConfig(), nil)
+	pool := NewEndpointPool(time.Minute, SandboxAPIUrl)
+	client.SetEndpointPool(pool)
+	assert.Equal(suite.T(), pool, client.transport.pool)
+}
+
+func (suite *ClientTestSuite) TestSetEndpointHook() {
+	client, _ := NewClientFromConfig(BuildStubConfig(), nil)
+	client.SetEndpointHook(func(endpoint string, resp *http.Response, err error) {})
+	assert.NotNil(suite.T(), client.transport.onEndpoint)
+}
+
+func (suite *ClientTestSuite) TestUse() {
+	client, _ := NewClientFromConfig(BuildStubConfig(), nil)
+	middleware := func(next RoundTripFunc) RoundTripFunc {
+		return next
+	}
+	client.Use(middleware, middleware)
+	assert.Len(suite.T(), client.transport.middlewares, 2)
+}
+
+func (suite *ClientTestSuite) TestSetLogger() {
+	client, _ := NewClientFromConfig(BuildStubConfig(), nil)
+	logger := &stubLogger{}
+	client.SetLogger(logger)
+	assert.Equal(suite.T(), logger, client.transport.logger)
+}
+
+func (suite *ClientTestSuite) TestSetMetrics() {
+	client, _ := NewClientFromConfig(BuildStubConfig(), nil)
+	metrics := NewInMemoryMetrics()
+	client.SetMetrics(metrics)
+	assert.Equal(suite.T(), metrics, client.transport.metrics)
+}
+
+func (suite *ClientTestSuite) TestSetTracer() {
+	client, _ := NewClientFromConfig(BuildStubConfig(), nil)
+	tracer := &stubTracer{}
+	client.SetTracer(tracer)
+	assert.Equal(suite.T(), tracer, client.transport.tracer)
+}
+
+func (suite *ClientTestSuite) TestSetTransferStore() {
+	client, _ := NewClientFromConfig(BuildStubConfig(), nil)
+	store := NewInMemoryTransferStore()
+	client.SetTransferStore(store)
+	assert.Equal(suite.T(), store, client.store)
+	assert.Equal(suite.T(), store, client.Transfers().store)
+}
+
+func (suite *ClientTestSuite) TestSetDryRun() {
+	client, _ := NewClientFromConfig(BuildStubConfig(), nil)
+	client.SetDryRun(true)
+	assert.True(suite.T(), client.dryRun)
+	assert.True(suite.T(), client.Transfers().dryRun)
+}
+
 func TestClientTestSuite(t *testing.T) {
 	suite.Run(t, new(ClientTestSuite))
 }
@@ -1,5 +1,10 @@
 package fasapay
 
+import (
+	"fmt"
+	"strings"
+)
+
 //CurrencyCode type
 type CurrencyCode string
 
@@ -21,23 +26,59 @@ const TransactionFeeModeFiS TransactionFeeMode = "FiS"
 //TransactionStatus type
 type TransactionStatus string
 
-//TransactionStatusFinish const
+//TransactionStatusFinish const - completed transaction (transaction detail and history response examples of FasaPay API reference)
 const TransactionStatusFinish TransactionStatus = "FINISH"
 
+//TransactionStatusCancel const - canceled transaction.
+//Not shown in FasaPay API reference response examples, the value is not confirmed by FasaPay documentation.
+const TransactionStatusCancel TransactionStatus = "CANCEL"
+
+//TransactionStatusFailed const - failed transaction.
+//Not shown in FasaPay API reference response examples, the value is not confirmed by FasaPay documentation.
+const TransactionStatusFailed TransactionStatus = "FAILED"
+
+//Normalize method - upper-cased status without surrounding spaces
+func (ts TransactionStatus) Normalize() TransactionStatus {
+	return TransactionStatus(strings.ToUpper(strings.TrimSpace(string(ts))))
+}
+
+//IsTerminal method - transaction will not change its status anymore (unknown statuses are not terminal)
+func (ts TransactionStatus) IsTerminal() bool {
+	switch ts.Normalize() {
+	case TransactionStatusFinish, TransactionStatusCancel, TransactionStatusFailed:
+		return true
+	}
+	return false
+}
+
+//IsSuccessful method - transaction is completed successfully (terminal statuses other than FINISH are unsuccessful)
+func (ts TransactionStatus) IsSuccessful() bool {
+	return ts.Normalize() == TransactionStatusFinish
+}
+
 //TransactionType type
 type TransactionType string
 
 //TransactionTypeTransfer const
-const TransactionTypeTransfer = "transfer"
+const TransactionTypeTransfer TransactionType = "transfer"
 
 //TransactionTypeTopUp const
-const TransactionTypeTopUp = "topup"
+const TransactionTypeTopUp TransactionType = "topup"
 
 //TransactionTypeRedeem const
-const TransactionTypeRedeem = "redeem"
+const TransactionTypeRedeem TransactionType = "redeem"
 
 //TransactionTypeExchange const
-const TransactionTypeExchange = "exchange"
+const TransactionTypeExchange TransactionType = "exchange"
 
 //TransactionTypeReceive const
-const TransactionTypeReceive = "receive"
+const TransactionTypeReceive TransactionType = "receive"
+
+//Validate method - check is transaction type supported
+func (tt TransactionType) Validate() error {
+	switch tt {
+	case TransactionTypeTransfer, TransactionTypeTopUp, TransactionTypeRedeem, TransactionTypeExchange, TransactionTypeReceive:
+		return nil
+	}
+	return fmt.Errorf(`transaction type "%s" is not supported`, tt)
+}
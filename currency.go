@@ -0,0 +1,110 @@
+package fasapay
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+//Currency struct - supported FasaPay currency description
+type Currency struct {
+	Code        CurrencyCode `json:"code"`
+	Precision   uint8        `json:"precision"`    //number of decimal places of the minor unit
+	MinTransfer Amount       `json:"min_transfer"` //min transfer amount (zero if not limited)
+	MaxTransfer Amount       `json:"max_transfer"` //max transfer amount (zero if not limited)
+}
+
+//currencyRegistry struct
+type currencyRegistry struct {
+	mu    sync.RWMutex
+	items map[CurrencyCode]*Currency
+}
+
+//currencies registry of supported currencies.
+//
+//FasaPay does not publish fixed transfer limits (they depend on the account level), so min/max transfer
+//amounts of the built-in IDR and USD currencies are unset and not checked before sending.
+//Use RegisterCurrency to set the limits of your account, e.g.
+//
+//    idr, _ := fasapay.LookupCurrency(fasapay.CurrencyCodeIDR)
+//    idr.MinTransfer = fasapay.MustParseAmount("10000")
+//    idr.MaxTransfer = fasapay.MustParseAmount("100000000")
+//    _ = fasapay.RegisterCurrency(idr)
+//
+var currencies = &currencyRegistry{items: map[CurrencyCode]*Currency{
+	CurrencyCodeIDR: {Code: CurrencyCodeIDR, Precision: 2},
+	CurrencyCodeUSD: {Code: CurrencyCodeUSD, Precision: 2},
+}}
+
+//RegisterCurrency method - add new or replace existing supported currency
+func RegisterCurrency(currency *Currency) error {
+	code := currency.Code.Normalize()
+	if code == "" {
+		return fmt.Errorf(`parameter "code" is empty`)
+	}
+	if currency.Precision > MaxAmountScale {
+		return fmt.Errorf(`parameter "precision" exceeds max scale %d`, MaxAmountScale)
+	}
+	item := *currency
+	item.Code = code
+	currencies.mu.Lock()
+	defer currencies.mu.Unlock()
+	currencies.items[code] = &item
+	return nil
+}
+
+//LookupCurrency method - find supported currency by code (case insensitive)
+func LookupCurrency(code CurrencyCode) (*Currency, bool) {
+	currencies.mu.RLock()
+	defer currencies.mu.RUnlock()
+	currency, ok := currencies.items[code.Normalize()]
+	if !ok {
+		return nil, false
+	}
+	item := *currency
+	return &item, true
+}
+
+//Currencies method - all supported currencies ordered by code
+func Currencies() []*Currency {
+	currencies.mu.RLock()
+	defer currencies.mu.RUnlock()
+	result := make([]*Currency, 0, len(currencies.items))
+	for _, currency := range currencies.items {
+		item := *currency
+		result = append(result, &item)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Code < result[j].Code
+	})
+	return result
+}
+
+//Normalize method - upper-cased currency code without surrounding spaces
+func (c CurrencyCode) Normalize() CurrencyCode {
+	return CurrencyCode(strings.ToUpper(strings.TrimSpace(string(c))))
+}
+
+//Validate method - check is currency code supported
+func (c CurrencyCode) Validate() error {
+	if c.Normalize() == "" {
+		return fmt.Errorf(`currency is empty`)
+	}
+	if _, ok := LookupCurrency(c); !ok {
+		return fmt.Errorf(`currency "%s" is not supported`, c)
+	}
+	return nil
+}
+
+//normalizeCurrencies Normalize and validate currency codes
+func normalizeCurrencies(codes []CurrencyCode) ([]CurrencyCode, error) {
+	result := make([]CurrencyCode, len(codes))
+	for i, code := range codes {
+		if err := code.Validate(); err != nil {
+			return nil, err
+		}
+		result[i] = code.Normalize()
+	}
+	return result, nil
+}
@@ -0,0 +1,107 @@
+package fasapay
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type CurrencyTestSuite struct {
+	suite.Suite
+}
+
+func (suite *CurrencyTestSuite) TearDownTest() {
+	currencies.mu.Lock()
+	delete(currencies.items, "EUR")
+	currencies.items[CurrencyCodeIDR] = &Currency{Code: CurrencyCodeIDR, Precision: 2}
+	currencies.mu.Unlock()
+}
+
+func (suite *CurrencyTestSuite) TestNormalize() {
+	assert.Equal(suite.T(), CurrencyCodeIDR, CurrencyCode("idr").Normalize())
+	assert.Equal(suite.T(), CurrencyCodeUSD, CurrencyCode(" Usd ").Normalize())
+	assert.Equal(suite.T(), CurrencyCode(""), CurrencyCode(" ").Normalize())
+}
+
+func (suite *CurrencyTestSuite) TestValidate() {
+	assert.NoError(suite.T(), CurrencyCodeIDR.Validate())
+	assert.NoError(suite.T(), CurrencyCode("usd").Validate())
+	assert.Equal(suite.T(), `currency is empty`, CurrencyCode("").Validate().Error())
+	assert.Equal(suite.T(), `currency "CHY" is not supported`, CurrencyCode("CHY").Validate().Error())
+}
+
+func (suite *CurrencyTestSuite) TestLookupCurrency() {
+	result, ok := LookupCurrency("idr")
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), CurrencyCodeIDR, result.Code)
+	assert.Equal(suite.T(), uint8(2), result.Precision)
+	result, ok = LookupCurrency("CHY")
+	assert.False(suite.T(), ok)
+	assert.Nil(suite.T(), result)
+}
+
+func (suite *CurrencyTestSuite) TestLookupCurrencyReturnsCopy() {
+	result, _ := LookupCurrency(CurrencyCodeUSD)
+	result.Precision = 5
+	result, _ = LookupCurrency(CurrencyCodeUSD)
+	assert.Equal(suite.T(), uint8(2), result.Precision)
+}
+
+func (suite *CurrencyTestSuite) TestRegisterCurrency() {
+	err := RegisterCurrency(&Currency{Code: "eur", Precision: 2, MaxTransfer: MustParseAmount("10000")})
+	assert.NoError(suite.T(), err)
+	result, ok := LookupCurrency("EUR")
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), CurrencyCode("EUR"), result.Code)
+	assert.Equal(suite.T(), MustParseAmount("10000"), result.MaxTransfer)
+	assert.NoError(suite.T(), CurrencyCode("eur").Validate())
+}
+
+func (suite *CurrencyTestSuite) TestDefaultLimitsUnset() {
+	for _, currency := range Currencies() {
+		assert.True(suite.T(), currency.MinTransfer.IsZero())
+		assert.True(suite.T(), currency.MaxTransfer.IsZero())
+	}
+	transfer := &CreateTransferRequestParams{To: "FP00001", Amount: MustParseAmount("0.01"), Currency: CurrencyCodeIDR}
+	assert.NoError(suite.T(), transfer.isValid())
+}
+
+func (suite *CurrencyTestSuite) TestRegisterCurrencyLimits() {
+	idr, _ := LookupCurrency(CurrencyCodeIDR)
+	idr.MinTransfer = MustParseAmount("10000")
+	idr.MaxTransfer = MustParseAmount("100000000")
+	assert.NoError(suite.T(), RegisterCurrency(idr))
+	transfer := &CreateTransferRequestParams{To: "FP00001", Amount: MustParseAmount("9999.99"), Currency: CurrencyCodeIDR}
+	assert.Equal(suite.T(), `parameter "amount" is less than min transfer 10000 IDR`, transfer.isValid().Error())
+	transfer.Amount = MustParseAmount("100000000.01")
+	assert.Equal(suite.T(), `parameter "amount" is greater than max transfer 100000000 IDR`, transfer.isValid().Error())
+	transfer.Amount = MustParseAmount("10000")
+	assert.NoError(suite.T(), transfer.isValid())
+}
+
+func (suite *CurrencyTestSuite) TestRegisterCurrencyInvalid() {
+	err := RegisterCurrency(&Currency{Code: " "})
+	assert.Equal(suite.T(), `parameter "code" is empty`, err.Error())
+	err = RegisterCurrency(&Currency{Code: "EUR", Precision: MaxAmountScale + 1})
+	assert.Error(suite.T(), err)
+}
+
+func (suite *CurrencyTestSuite) TestCurrencies() {
+	result := Currencies()
+	assert.Len(suite.T(), result, 2)
+	assert.Equal(suite.T(), CurrencyCodeIDR, result[0].Code)
+	assert.Equal(suite.T(), CurrencyCodeUSD, result[1].Code)
+}
+
+func (suite *CurrencyTestSuite) TestNormalizeCurrencies() {
+	result, err := normalizeCurrencies([]CurrencyCode{"idr", "USD"})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []CurrencyCode{CurrencyCodeIDR, CurrencyCodeUSD}, result)
+	result, err = normalizeCurrencies([]CurrencyCode{"idr", "CHY"})
+	assert.Error(suite.T(), err)
+	assert.Nil(suite.T(), result)
+}
+
+func TestCurrencyTestSuite(t *testing.T) {
+	suite.Run(t, new(CurrencyTestSuite))
+}
@@ -0,0 +1,67 @@
+package fasapay
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"time"
+)
+
+//DryRunBatchNumberPrefix prefix of batch numbers of simulated transfers
+const DryRunBatchNumberPrefix = "DRYRUN"
+
+//dryRunContextKey struct
+type dryRunContextKey struct{}
+
+//WithDryRun Create context enabling dry-run of CreateTransfer: transfers are validated and logged, but not sent
+func WithDryRun(ctx context.Context) context.Context {
+	return context.WithValue(ctx, dryRunContextKey{}, true)
+}
+
+//IsDryRun Check is dry-run enabled by context
+func IsDryRun(ctx context.Context) bool {
+	enabled, _ := ctx.Value(dryRunContextKey{}).(bool)
+	return enabled
+}
+
+//simulateCreateTransfer method - build and log request document, return simulated response without sending the request
+func (r *TransfersResource) simulateCreateTransfer(transfers []*CreateTransferRequestParams, attributes *RequestParamsAttributes) (*CreateTransferResponse, error) {
+	requestParams := &CreateTransferRequest{r.buildRequestParams(attributes), transfers}
+	document, err := xml.Marshal(requestParams)
+	if err != nil {
+		return nil, err
+	}
+	redacted := string(redactCredentials(document))
+	if r.tr.logger != nil {
+		r.tr.logger.Info("fasapay dry run", "operation", string(OperationCreateTransfer), "request_id", requestParams.Id, "request", redacted)
+	}
+	now := time.Now().UTC()
+	result := &CreateTransferResponse{
+		ResponseBody:     ResponseBody{Id: requestParams.Id, DateTime: now.Format(time.RFC3339)},
+		Simulated:        true,
+		SimulatedRequest: redacted,
+	}
+	for index, transfer := range transfers {
+		quote, err := QuoteTransfer(transfer, nil)
+		if err != nil {
+			quote = &TransferQuote{FeeMode: transfer.FeeMode, Total: transfer.Amount}
+		}
+		result.Transfers = append(result.Transfers, &CreateTransferResponseParams{
+			Mode:        "transfer",
+			BatchNumber: fmt.Sprintf("%s%s%04d", DryRunBatchNumberPrefix, now.Format("20060102150405"), index+1),
+			Date:        now.Format("2006-01-02"),
+			Time:        now.Format("15:04:05"),
+			To:          transfer.To,
+			Fee:         quote.Fee,
+			Amount:      transfer.Amount,
+			Total:       quote.Total,
+			FeeMode:     string(quote.FeeMode),
+			Currency:    string(transfer.Currency),
+			Note:        transfer.Note,
+			Status:      TransactionStatusFinish,
+			Method:      "dry_run",
+		})
+	}
+	result.Results = correlateTransferResults(transfers, result)
+	return result, nil
+}
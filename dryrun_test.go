@@ -0,0 +1,117 @@
+package fasapay
+
+import (
+	"context"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"strings"
+	"testing"
+	"time"
+)
+
+type DryRunTestSuite struct {
+	suite.Suite
+	ctx      context.Context
+	client   *Client
+	logger   *stubLogger
+	attrs    *RequestParamsAttributes
+	transfer *CreateTransferRequestParams
+}
+
+func (suite *DryRunTestSuite) SetupTest() {
+	suite.ctx = context.Background()
+	suite.client, _ = NewClientFromConfig(BuildStubConfig(), nil)
+	suite.logger = &stubLogger{}
+	suite.client.SetLogger(suite.logger)
+	suite.attrs = &RequestParamsAttributes{Id: "1234567", DateTime: time.Now()}
+	suite.transfer = &CreateTransferRequestParams{Id: "tid1", To: "FP89680", Amount: MustParseAmount("1000"), Currency: CurrencyCodeIDR, FeeMode: TransactionFeeModeFiS, Note: "standart operation"}
+	httpmock.Activate()
+}
+
+func (suite *DryRunTestSuite) TearDownTest() {
+	httpmock.DeactivateAndReset()
+}
+
+func (suite *DryRunTestSuite) TestIsDryRun() {
+	assert.False(suite.T(), IsDryRun(suite.ctx))
+	assert.True(suite.T(), IsDryRun(WithDryRun(suite.ctx)))
+}
+
+func (suite *DryRunTestSuite) TestCreateTransferDryRunContext() {
+	result, resp, err := suite.client.Transfers().CreateTransfer([]*CreateTransferRequestParams{suite.transfer}, WithDryRun(suite.ctx), suite.attrs)
+	assert.NoError(suite.T(), err)
+	assert.Nil(suite.T(), resp)
+	assert.Equal(suite.T(), 0, httpmock.GetTotalCallCount())
+
+	assert.True(suite.T(), result.Simulated)
+	assert.True(suite.T(), result.IsSuccess())
+	assert.Equal(suite.T(), "1234567", result.Id)
+	assert.Len(suite.T(), result.Transfers, 1)
+	assert.True(suite.T(), strings.HasPrefix(result.Transfers[0].BatchNumber, DryRunBatchNumberPrefix))
+	assert.Equal(suite.T(), AccountNumber("FP89680"), result.Transfers[0].To)
+	assert.Equal(suite.T(), "100.00", result.Transfers[0].Fee.String())
+	assert.Equal(suite.T(), "1100.00", result.Transfers[0].Total.String())
+	assert.Equal(suite.T(), "FiS", result.Transfers[0].FeeMode)
+	assert.Equal(suite.T(), TransactionStatusFinish, result.Transfers[0].Status)
+	assert.Equal(suite.T(), TransferResultStatusAccepted, result.Results[0].Status)
+
+	assert.Contains(suite.T(), result.SimulatedRequest, `<transfer id="tid1"><to>FP89680</to><amount>1000</amount><currency>IDR</currency><fee_mode>FiS</fee_mode><note>standart operation</note></transfer>`)
+	assert.Contains(suite.T(), result.SimulatedRequest, `<api_key>***</api_key>`)
+	assert.Len(suite.T(), suite.logger.entries, 1)
+	assert.Equal(suite.T(), "fasapay dry run", suite.logger.entries[0].msg)
+	assert.Equal(suite.T(), string(OperationCreateTransfer), suite.logger.entries[0].args["operation"])
+	assert.Equal(suite.T(), result.SimulatedRequest, suite.logger.entries[0].args["request"])
+}
+
+func (suite *DryRunTestSuite) TestCreateTransferDryRunClient() {
+	suite.client.SetDryRun(true)
+	suite.client.SetTransferStore(NewInMemoryTransferStore())
+	result, _, err := suite.client.Transfers().CreateTransfer([]*CreateTransferRequestParams{suite.transfer, suite.transfer}, suite.ctx, nil)
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), result.Simulated)
+	assert.Len(suite.T(), result.Transfers, 2)
+	assert.NotEqual(suite.T(), result.Transfers[0].BatchNumber, result.Transfers[1].BatchNumber)
+	assert.Equal(suite.T(), 0, httpmock.GetTotalCallCount())
+
+	stuck, _ := suite.client.store.ListStuck(0)
+	assert.Empty(suite.T(), stuck)
+}
+
+func (suite *DryRunTestSuite) TestCreateTransferDryRunStoreRefs() {
+	suite.client.SetTransferStore(NewInMemoryTransferStore())
+	transfer := &CreateTransferRequestParams{To: "FP89680", Amount: MustParseAmount("1000"), Currency: CurrencyCodeIDR}
+	params := []*CreateTransferRequestParams{suite.transfer, transfer}
+	result, _, err := suite.client.Transfers().CreateTransfer(params, WithDryRun(suite.ctx), suite.attrs)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 0, httpmock.GetTotalCallCount())
+
+	ref := newTransferRefFromId("tid1")
+	assert.Contains(suite.T(), result.SimulatedRequest, `<transfer id="tid1"><to>FP89680</to><amount>1000</amount><currency>IDR</currency><fee_mode>FiS</fee_mode><note>standart operation</note><ref>`+ref+`</ref></transfer>`)
+	assert.Regexp(suite.T(), `<transfer id="([0-9a-f]{32})"><to>FP89680</to><amount>1000</amount><currency>IDR</currency><ref>([0-9a-f]{32})</ref></transfer>`, result.SimulatedRequest)
+	assert.Empty(suite.T(), suite.transfer.Ref)
+	assert.Empty(suite.T(), transfer.Id)
+	assert.Empty(suite.T(), transfer.Ref)
+	stuck, _ := suite.client.store.ListStuck(0)
+	assert.Empty(suite.T(), stuck)
+}
+
+func (suite *DryRunTestSuite) TestCreateTransferDryRunInvalid() {
+	suite.transfer.To = ""
+	result, _, err := suite.client.Transfers().CreateTransfer([]*CreateTransferRequestParams{suite.transfer}, WithDryRun(suite.ctx), nil)
+	assert.Nil(suite.T(), result)
+	assert.Equal(suite.T(), `TransfersResource.CreateTransfer error: parameter "to" is empty`, err.Error())
+	assert.Empty(suite.T(), suite.logger.entries)
+}
+
+func (suite *DryRunTestSuite) TestBulkTransferDryRun() {
+	suite.client.SetDryRun(true)
+	report, err := suite.client.Transfers().BulkTransfer([]*CreateTransferRequestParams{suite.transfer}, suite.ctx, nil, nil)
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), report.IsSuccess())
+	assert.Equal(suite.T(), 0, httpmock.GetTotalCallCount())
+}
+
+func TestDryRunTestSuite(t *testing.T) {
+	suite.Run(t, new(DryRunTestSuite))
+}
@@ -0,0 +1,136 @@
+package fasapay
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+//DefaultEndpointCooldown period while failed endpoint is not used (if other endpoints are available)
+const DefaultEndpointCooldown = 30 * time.Second
+
+//EndpointHook func - report which endpoint served the request (resp and err are the result of the attempt)
+type EndpointHook func(endpoint string, resp *http.Response, err error)
+
+//endpointState struct
+type endpointState struct {
+	uri       string
+	failures  uint64
+	downUntil time.Time
+}
+
+//EndpointPool struct - health-tracked API endpoints list in priority order
+type EndpointPool struct {
+	mu        sync.Mutex
+	endpoints []*endpointState
+	cooldown  time.Duration
+	now       func() time.Time
+}
+
+//NewEndpointPool Create new endpoint pool, the first endpoint is the primary one
+func NewEndpointPool(cooldown time.Duration, endpoints ...string) *EndpointPool {
+	pool := &EndpointPool{cooldown: cooldown, now: time.Now}
+	for _, uri := range endpoints {
+		pool.endpoints = append(pool.endpoints, &endpointState{uri: uri})
+	}
+	return pool
+}
+
+//newEndpointPoolFromConfig Create endpoint pool from config (both production urls for production config)
+func newEndpointPoolFromConfig(config *Config) *EndpointPool {
+	if config.IsSandbox() {
+		return NewEndpointPool(DefaultEndpointCooldown, config.Uri)
+	}
+	second := ProdAPIUrlSecond
+	if config.Uri == ProdAPIUrlSecond {
+		second = ProdAPIUrl
+	}
+	return NewEndpointPool(DefaultEndpointCooldown, config.Uri, second)
+}
+
+//Endpoints method - all endpoints in priority order
+func (p *EndpointPool) Endpoints() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	endpoints := make([]string, len(p.endpoints))
+	for i, endpoint := range p.endpoints {
+		endpoints[i] = endpoint.uri
+	}
+	return endpoints
+}
+
+//IsHealthy method - check is endpoint not in cool-down
+func (p *EndpointPool) IsHealthy(uri string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, endpoint := range p.endpoints {
+		if endpoint.uri == uri {
+			return !p.now().Before(endpoint.downUntil)
+		}
+	}
+	return false
+}
+
+//MarkSuccess method - mark endpoint as healthy
+func (p *EndpointPool) MarkSuccess(uri string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, endpoint := range p.endpoints {
+		if endpoint.uri == uri {
+			endpoint.failures = 0
+			endpoint.downUntil = time.Time{}
+		}
+	}
+}
+
+//MarkFailure method - put endpoint in cool-down
+func (p *EndpointPool) MarkFailure(uri string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, endpoint := range p.endpoints {
+		if endpoint.uri == uri {
+			endpoint.failures++
+			endpoint.downUntil = p.now().Add(p.cooldown)
+		}
+	}
+}
+
+//candidates method - healthy endpoints in priority order, then endpoints in cool-down ordered by recovery time
+func (p *EndpointPool) candidates() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := p.now()
+	var healthy []string
+	var unhealthy []*endpointState
+	for _, endpoint := range p.endpoints {
+		if now.Before(endpoint.downUntil) {
+			unhealthy = append(unhealthy, endpoint)
+		} else {
+			healthy = append(healthy, endpoint.uri)
+		}
+	}
+	sort.SliceStable(unhealthy, func(i, j int) bool {
+		return unhealthy[i].downUntil.Before(unhealthy[j].downUntil)
+	})
+	for _, endpoint := range unhealthy {
+		healthy = append(healthy, endpoint.uri)
+	}
+	return healthy
+}
+
+//isEndpointFailure check is attempt result an endpoint failure (connection error or 5xx status code)
+func isEndpointFailure(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode >= http.StatusInternalServerError
+}
+
+//isDialError check is error a connection establishment error (request was not sent)
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
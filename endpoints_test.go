@@ -0,0 +1,85 @@
+package fasapay
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type EndpointPoolTestSuite struct {
+	suite.Suite
+	now      time.Time
+	testable *EndpointPool
+}
+
+func (suite *EndpointPoolTestSuite) SetupTest() {
+	suite.now = BuildStubDateTime()
+	suite.testable = NewEndpointPool(time.Minute, ProdAPIUrl, ProdAPIUrlSecond)
+	suite.testable.now = func() time.Time {
+		return suite.now
+	}
+}
+
+func (suite *EndpointPoolTestSuite) TestEndpoints() {
+	assert.Equal(suite.T(), []string{ProdAPIUrl, ProdAPIUrlSecond}, suite.testable.Endpoints())
+	assert.Equal(suite.T(), []string{ProdAPIUrl, ProdAPIUrlSecond}, suite.testable.candidates())
+}
+
+func (suite *EndpointPoolTestSuite) TestFailoverAndFailback() {
+	suite.testable.MarkFailure(ProdAPIUrl)
+	assert.False(suite.T(), suite.testable.IsHealthy(ProdAPIUrl))
+	assert.True(suite.T(), suite.testable.IsHealthy(ProdAPIUrlSecond))
+	assert.Equal(suite.T(), []string{ProdAPIUrlSecond, ProdAPIUrl}, suite.testable.candidates())
+
+	suite.now = suite.now.Add(time.Minute)
+	assert.True(suite.T(), suite.testable.IsHealthy(ProdAPIUrl))
+	assert.Equal(suite.T(), []string{ProdAPIUrl, ProdAPIUrlSecond}, suite.testable.candidates())
+}
+
+func (suite *EndpointPoolTestSuite) TestAllEndpointsFailed() {
+	suite.testable.MarkFailure(ProdAPIUrlSecond)
+	suite.now = suite.now.Add(time.Second)
+	suite.testable.MarkFailure(ProdAPIUrl)
+	assert.Equal(suite.T(), []string{ProdAPIUrlSecond, ProdAPIUrl}, suite.testable.candidates())
+}
+
+func (suite *EndpointPoolTestSuite) TestMarkSuccess() {
+	suite.testable.MarkFailure(ProdAPIUrl)
+	suite.testable.MarkSuccess(ProdAPIUrl)
+	assert.True(suite.T(), suite.testable.IsHealthy(ProdAPIUrl))
+	assert.Equal(suite.T(), []string{ProdAPIUrl, ProdAPIUrlSecond}, suite.testable.candidates())
+}
+
+func (suite *EndpointPoolTestSuite) TestIsHealthyUnknownEndpoint() {
+	assert.False(suite.T(), suite.testable.IsHealthy(SandboxAPIUrl))
+}
+
+func (suite *EndpointPoolTestSuite) TestNewEndpointPoolFromConfig() {
+	cfg := NewConfig("foo", "bar")
+	assert.Equal(suite.T(), []string{ProdAPIUrl, ProdAPIUrlSecond}, newEndpointPoolFromConfig(cfg).Endpoints())
+	cfg.Uri = ProdAPIUrlSecond
+	assert.Equal(suite.T(), []string{ProdAPIUrlSecond, ProdAPIUrl}, newEndpointPoolFromConfig(cfg).Endpoints())
+	cfg = NewConfigSandbox("foo", "bar")
+	assert.Equal(suite.T(), []string{SandboxAPIUrl}, newEndpointPoolFromConfig(cfg).Endpoints())
+}
+
+func (suite *EndpointPoolTestSuite) TestIsEndpointFailure() {
+	assert.True(suite.T(), isEndpointFailure(nil, errors.New("connection reset by peer")))
+	assert.True(suite.T(), isEndpointFailure(&http.Response{StatusCode: http.StatusBadGateway}, nil))
+	assert.False(suite.T(), isEndpointFailure(&http.Response{StatusCode: http.StatusBadRequest}, nil))
+	assert.False(suite.T(), isEndpointFailure(&http.Response{StatusCode: http.StatusOK}, nil))
+}
+
+func (suite *EndpointPoolTestSuite) TestIsDialError() {
+	assert.True(suite.T(), isDialError(&net.OpError{Op: "dial", Err: errors.New("connection refused")}))
+	assert.False(suite.T(), isDialError(&net.OpError{Op: "read", Err: errors.New("connection reset by peer")}))
+	assert.False(suite.T(), isDialError(errors.New("foo")))
+}
+
+func TestEndpointPoolTestSuite(t *testing.T) {
+	suite.Run(t, new(EndpointPoolTestSuite))
+}
@@ -1,5 +1,11 @@
 package fasapay
 
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
 const (
 	//ErrorCodeNotValidXmlRequest The sent XML are not valid, broken or has wrong format
 	ErrorCodeNotValidXmlRequest uint64 = 40000
@@ -35,3 +41,187 @@ const (
 	//ErrorMessageUnexpectedError Unexpected error
 	ErrorMessageUnexpectedError string = "UNEXPECTED ERROR"
 )
+
+const (
+	//ErrorCodeUnknownRecipient There is no user with the recipient account number (transfer operation)
+	ErrorCodeUnknownRecipient uint64 = 40601
+	//ErrorCodeAmountOverLimit The transferred amount exceeds the allowed limit (transfer operation)
+	ErrorCodeAmountOverLimit uint64 = 40602
+	//ErrorCodeEmptyCurrency The transfer currency is empty (transfer operation)
+	ErrorCodeEmptyCurrency uint64 = 40605
+	//ErrorCodeTransactionNotFound The requested transaction was not found (detail operation)
+	ErrorCodeTransactionNotFound uint64 = 40701
+	//ErrorCodeWrongOrInactiveCurrency The requested currency is wrong or inactive (balance operation)
+	ErrorCodeWrongOrInactiveCurrency uint64 = 40901
+	//ErrorCodeAccountNotFound The requested account was not found (account operation)
+	ErrorCodeAccountNotFound uint64 = 41001
+)
+
+const (
+	//ErrorMessageUnknownRecipient There is no user with the recipient account number
+	ErrorMessageUnknownRecipient string = "UNKNOWN RECIPIENT ACCOUNT"
+	//ErrorMessageAmountOverLimit The transferred amount exceeds the allowed limit
+	ErrorMessageAmountOverLimit string = "AMOUNT OVER LIMIT"
+	//ErrorMessageEmptyCurrency The transfer currency is empty
+	ErrorMessageEmptyCurrency string = "EMPTY CURRENCY"
+	//ErrorMessageTransactionNotFound The requested transaction was not found
+	ErrorMessageTransactionNotFound string = "TRANSACTION NOT FOUND"
+	//ErrorMessageWrongOrInactiveCurrency The requested currency is wrong or inactive
+	ErrorMessageWrongOrInactiveCurrency string = "WRONG OR INACTIVE CURRENCY"
+	//ErrorMessageAccountNotFound The requested account was not found
+	ErrorMessageAccountNotFound string = "ACCOUNT NOT FOUND"
+)
+
+//CodeError struct - documented FasaPay error code, used as sentinel error with errors.Is
+type CodeError struct {
+	Code    uint64 `json:"code"`
+	Message string `json:"message"`
+}
+
+//Error method implementation
+func (e *CodeError) Error() string {
+	return e.Message
+}
+
+var (
+	//ErrNotValidXmlRequest The sent XML are not valid, broken or has wrong format
+	ErrNotValidXmlRequest = &CodeError{ErrorCodeNotValidXmlRequest, ErrorMessageNotValidXmlRequest}
+	//ErrUnauthorized Authorisation failed.
+	ErrUnauthorized = &CodeError{ErrorCodeUnauthorized, ErrorMessageUnauthorized}
+	//ErrNotAcceptableTransfer There is an error in the transfer operation
+	ErrNotAcceptableTransfer = &CodeError{ErrorCodeNotAcceptableTransfer, ErrorMessageNotAcceptableTransfer}
+	//ErrDetailRequest There is an error in the detail operation
+	ErrDetailRequest = &CodeError{ErrorCodeDetailRequestError, ErrorMessageDetailRequestError}
+	//ErrHistoryRequest There is an error in the history operation
+	ErrHistoryRequest = &CodeError{ErrorCodeHistoryRequestError, ErrorMessageHistoryRequestError}
+	//ErrBalanceRequest There is an error in the balance operation
+	ErrBalanceRequest = &CodeError{ErrorCodeBalanceRequestError, ErrorMessageBalanceRequestError}
+	//ErrAccountRequest There is an error in the account operation
+	ErrAccountRequest = &CodeError{ErrorCodeAccountRequestError, ErrorMessageAccountRequestError}
+	//ErrUnknownRecipient There is no user with the recipient account number
+	ErrUnknownRecipient = &CodeError{ErrorCodeUnknownRecipient, ErrorMessageUnknownRecipient}
+	//ErrAmountOverLimit The transferred amount exceeds the allowed limit
+	ErrAmountOverLimit = &CodeError{ErrorCodeAmountOverLimit, ErrorMessageAmountOverLimit}
+	//ErrEmptyCurrency The transfer currency is empty
+	ErrEmptyCurrency = &CodeError{ErrorCodeEmptyCurrency, ErrorMessageEmptyCurrency}
+	//ErrTransactionNotFound The requested transaction was not found
+	ErrTransactionNotFound = &CodeError{ErrorCodeTransactionNotFound, ErrorMessageTransactionNotFound}
+	//ErrWrongOrInactiveCurrency The requested currency is wrong or inactive
+	ErrWrongOrInactiveCurrency = &CodeError{ErrorCodeWrongOrInactiveCurrency, ErrorMessageWrongOrInactiveCurrency}
+	//ErrAccountNotFound The requested account was not found
+	ErrAccountNotFound = &CodeError{ErrorCodeAccountNotFound, ErrorMessageAccountNotFound}
+)
+
+//errorsCatalogue documented error codes catalogue
+var errorsCatalogue = map[uint64]*CodeError{
+	ErrorCodeNotValidXmlRequest:      ErrNotValidXmlRequest,
+	ErrorCodeUnauthorized:            ErrUnauthorized,
+	ErrorCodeNotAcceptableTransfer:   ErrNotAcceptableTransfer,
+	ErrorCodeDetailRequestError:      ErrDetailRequest,
+	ErrorCodeHistoryRequestError:     ErrHistoryRequest,
+	ErrorCodeBalanceRequestError:     ErrBalanceRequest,
+	ErrorCodeAccountRequestError:     ErrAccountRequest,
+	ErrorCodeUnknownRecipient:        ErrUnknownRecipient,
+	ErrorCodeAmountOverLimit:         ErrAmountOverLimit,
+	ErrorCodeEmptyCurrency:           ErrEmptyCurrency,
+	ErrorCodeTransactionNotFound:     ErrTransactionNotFound,
+	ErrorCodeWrongOrInactiveCurrency: ErrWrongOrInactiveCurrency,
+	ErrorCodeAccountNotFound:         ErrAccountNotFound,
+}
+
+//LookupErrorCode method - find documented error by code
+func LookupErrorCode(code uint64) (*CodeError, bool) {
+	err, ok := errorsCatalogue[code]
+	return err, ok
+}
+
+//APIError struct - failed fasa_response returned by FasaPay API
+type APIError struct {
+	Id      string                     `json:"id,omitempty"` //id of the transfer which caused the error (transfer operation only)
+	Mode    string                     `json:"mode"`         //operation mode (transfer|detail|history|balance|account)
+	Code    uint64                     `json:"code"`         //top-level error code
+	Message string                     `json:"message"`      //top-level error message
+	Errors  []*ResponseBodyErrorParams `json:"errors"`       //sub-errors list
+}
+
+//Error method implementation
+func (e *APIError) Error() string {
+	return e.Message
+}
+
+//HasErrorCode method - check is top-level code or any of sub-errors codes is equal to code
+func (e *APIError) HasErrorCode(code uint64) bool {
+	if e.Code == code {
+		return true
+	}
+	for _, item := range e.Errors {
+		if item.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+//hasErrorFamily method - check is top-level code or any of sub-errors codes belongs to operation family (e.g. 40901 to 40900)
+func (e *APIError) hasErrorFamily(family uint64) bool {
+	if e.Code/100*100 == family {
+		return true
+	}
+	for _, item := range e.Errors {
+		if item.Code/100*100 == family {
+			return true
+		}
+	}
+	return false
+}
+
+//Is method implementation - allow errors.Is to match documented error codes and operation error families
+func (e *APIError) Is(target error) bool {
+	var codeErr *CodeError
+	if !errors.As(target, &codeErr) {
+		return false
+	}
+	if codeErr.Code%100 == 0 && e.hasErrorFamily(codeErr.Code) {
+		return true
+	}
+	return e.HasErrorCode(codeErr.Code)
+}
+
+//newAPIError Create new API error from response body errors
+func newAPIError(errors *ResponseBodyErrors, message string) *APIError {
+	return &APIError{
+		Id:      errors.Id,
+		Mode:    errors.Mode,
+		Code:    errors.Code,
+		Message: message,
+		Errors:  errors.Data,
+	}
+}
+
+//httpErrorBodySnippetLength max length of response body snippet in HTTPError
+const httpErrorBodySnippetLength = 256
+
+//HTTPError struct - unexpected HTTP response (non 2xx status code or body is not a fasa_response document)
+type HTTPError struct {
+	StatusCode  int    `json:"status_code"`
+	ContentType string `json:"content_type"`
+	Body        string `json:"body"` //truncated response body snippet
+}
+
+//Error method implementation
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("unexpected HTTP response: status %d, content type %q, body %q", e.StatusCode, e.ContentType, e.Body)
+}
+
+//newHTTPError Create new HTTP error from response and its body
+func newHTTPError(resp *http.Response, body []byte) *HTTPError {
+	snippet := body
+	if len(snippet) > httpErrorBodySnippetLength {
+		snippet = snippet[:httpErrorBodySnippetLength]
+	}
+	return &HTTPError{
+		StatusCode:  resp.StatusCode,
+		ContentType: resp.Header.Get("Content-Type"),
+		Body:        string(snippet),
+	}
+}
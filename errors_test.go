@@ -0,0 +1,118 @@
+package fasapay
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type APIErrorTestSuite struct {
+	suite.Suite
+}
+
+func (suite *APIErrorTestSuite) TestNewAPIError() {
+	errs := &ResponseBodyErrors{Id: "tid3", Mode: "transfer", Code: ErrorCodeNotAcceptableTransfer, Data: []*ResponseBodyErrorParams{
+		{Code: 40601, Attribute: "to", Message: "Tidak ada User dengan Nomor Akun FP89681"},
+	}}
+	result := newAPIError(errs, ErrorMessageNotAcceptableTransfer)
+	assert.Equal(suite.T(), "tid3", result.Id)
+	assert.Equal(suite.T(), "transfer", result.Mode)
+	assert.Equal(suite.T(), ErrorCodeNotAcceptableTransfer, result.Code)
+	assert.Equal(suite.T(), ErrorMessageNotAcceptableTransfer, result.Message)
+	assert.Equal(suite.T(), ErrorMessageNotAcceptableTransfer, result.Error())
+	assert.Len(suite.T(), result.Errors, 1)
+	assert.Equal(suite.T(), "to", result.Errors[0].Attribute)
+}
+
+func (suite *APIErrorTestSuite) TestHasErrorCode() {
+	result := &APIError{Code: ErrorCodeNotAcceptableTransfer, Errors: []*ResponseBodyErrorParams{{Code: 40601}, {Code: 40602}}}
+	assert.True(suite.T(), result.HasErrorCode(ErrorCodeNotAcceptableTransfer))
+	assert.True(suite.T(), result.HasErrorCode(40601))
+	assert.True(suite.T(), result.HasErrorCode(40602))
+	assert.False(suite.T(), result.HasErrorCode(40605))
+}
+
+func (suite *APIErrorTestSuite) TestErrorsAs() {
+	err := fmt.Errorf("wrapped: %w", &APIError{Code: ErrorCodeUnauthorized, Message: ErrorMessageUnauthorized})
+	var apiErr *APIError
+	assert.True(suite.T(), errors.As(err, &apiErr))
+	assert.Equal(suite.T(), ErrorCodeUnauthorized, apiErr.Code)
+}
+
+func (suite *APIErrorTestSuite) TestErrorsIs() {
+	result := &APIError{Code: ErrorCodeNotAcceptableTransfer, Errors: []*ResponseBodyErrorParams{{Code: 40601}, {Code: 40602}}}
+	err := fmt.Errorf("wrapped: %w", result)
+	assert.True(suite.T(), errors.Is(err, ErrNotAcceptableTransfer))
+	assert.True(suite.T(), errors.Is(err, ErrUnknownRecipient))
+	assert.True(suite.T(), errors.Is(err, ErrAmountOverLimit))
+	assert.False(suite.T(), errors.Is(err, ErrEmptyCurrency))
+	assert.False(suite.T(), errors.Is(err, ErrUnauthorized))
+	assert.False(suite.T(), errors.Is(err, errors.New("foo")))
+}
+
+func (suite *APIErrorTestSuite) TestErrorsIsFamily() {
+	err := fmt.Errorf("wrapped: %w", &APIError{Code: ErrorCodeWrongOrInactiveCurrency})
+	assert.True(suite.T(), errors.Is(err, ErrBalanceRequest))
+	assert.True(suite.T(), errors.Is(err, ErrWrongOrInactiveCurrency))
+	assert.False(suite.T(), errors.Is(err, ErrAccountRequest))
+	assert.False(suite.T(), errors.Is(err, ErrAccountNotFound))
+	err = &APIError{Code: ErrorCodeAccountRequestError, Errors: []*ResponseBodyErrorParams{{Code: 41001}}}
+	assert.True(suite.T(), errors.Is(err, ErrAccountRequest))
+	assert.True(suite.T(), errors.Is(err, ErrAccountNotFound))
+	assert.False(suite.T(), errors.Is(err, ErrBalanceRequest))
+}
+
+func (suite *APIErrorTestSuite) TestGetErrorMessage() {
+	body := &ResponseBody{Errors: &ResponseBodyErrors{Code: ErrorCodeAccountNotFound}}
+	assert.Equal(suite.T(), ErrorMessageAccountNotFound, body.GetError())
+	body = &ResponseBody{Errors: &ResponseBodyErrors{Code: ErrorCodeBalanceRequestError}}
+	assert.Equal(suite.T(), ErrorMessageBalanceRequestError, body.GetError())
+	body = &ResponseBody{Errors: &ResponseBodyErrors{Code: 49999}}
+	assert.Equal(suite.T(), ErrorMessageUnexpectedError, body.GetError())
+}
+
+func (suite *APIErrorTestSuite) TestLookupErrorCode() {
+	result, ok := LookupErrorCode(40605)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), ErrEmptyCurrency, result)
+	assert.Equal(suite.T(), ErrorMessageEmptyCurrency, result.Error())
+	result, ok = LookupErrorCode(ErrorCodeUnauthorized)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), ErrUnauthorized, result)
+	result, ok = LookupErrorCode(1)
+	assert.False(suite.T(), ok)
+	assert.Nil(suite.T(), result)
+}
+
+func TestAPIErrorTestSuite(t *testing.T) {
+	suite.Run(t, new(APIErrorTestSuite))
+}
+
+type HTTPErrorTestSuite struct {
+	suite.Suite
+}
+
+func (suite *HTTPErrorTestSuite) TestNewHTTPError() {
+	rsp := BuildStubResponseFromString(http.StatusBadGateway, "")
+	rsp.Header = http.Header{"Content-Type": []string{"text/html"}}
+	result := newHTTPError(rsp, []byte("Bad gateway"))
+	assert.Equal(suite.T(), http.StatusBadGateway, result.StatusCode)
+	assert.Equal(suite.T(), "text/html", result.ContentType)
+	assert.Equal(suite.T(), "Bad gateway", result.Body)
+	assert.Equal(suite.T(), `unexpected HTTP response: status 502, content type "text/html", body "Bad gateway"`, result.Error())
+}
+
+func (suite *HTTPErrorTestSuite) TestNewHTTPErrorTruncateBody() {
+	rsp := BuildStubResponseFromString(http.StatusOK, "")
+	result := newHTTPError(rsp, []byte(strings.Repeat("a", httpErrorBodySnippetLength+10)))
+	assert.Equal(suite.T(), "", result.ContentType)
+	assert.Len(suite.T(), result.Body, httpErrorBodySnippetLength)
+}
+
+func TestHTTPErrorTestSuite(t *testing.T) {
+	suite.Run(t, new(HTTPErrorTestSuite))
+}
@@ -0,0 +1,120 @@
+package fasapay
+
+import "fmt"
+
+//FeeRule struct - transfer fee rule of the currency
+type FeeRule struct {
+	Percent Amount `json:"percent"` //fee percent of the transfer amount
+	Min     Amount `json:"min"`     //min fee (zero if not limited)
+	Max     Amount `json:"max"`     //max fee (zero if not limited)
+}
+
+//calculate method - fee of amount rounded to precision decimal places
+func (fr *FeeRule) calculate(amount Amount, precision uint8) Amount {
+	fee := amount.Percent(fr.Percent, precision)
+	if !fr.Min.IsZero() && fee.Cmp(fr.Min) < 0 {
+		fee = fr.Min.Rescale(precision)
+	}
+	if !fr.Max.IsZero() && fee.Cmp(fr.Max) > 0 {
+		fee = fr.Max.Rescale(precision)
+	}
+	return fee
+}
+
+//FeeSchedule struct - transfer fee rules per currency
+type FeeSchedule struct {
+	Rules map[CurrencyCode]*FeeRule `json:"rules"`
+}
+
+//NewFeeSchedule Create new empty fee schedule
+func NewFeeSchedule() *FeeSchedule {
+	return &FeeSchedule{Rules: map[CurrencyCode]*FeeRule{}}
+}
+
+//DefaultFeeSchedule Create fee schedule with FasaPay default transfer fees (0.5% with per currency min/max limits).
+//FasaPay may apply individual fees to your account, use NewFeeSchedule to describe them.
+func DefaultFeeSchedule() *FeeSchedule {
+	return NewFeeSchedule().
+		SetRule(CurrencyCodeIDR, &FeeRule{Percent: MustParseAmount("0.5"), Min: MustParseAmount("100"), Max: MustParseAmount("50000")}).
+		SetRule(CurrencyCodeUSD, &FeeRule{Percent: MustParseAmount("0.5"), Min: MustParseAmount("0.01"), Max: MustParseAmount("5")})
+}
+
+//SetRule method - set fee rule of the currency
+func (fs *FeeSchedule) SetRule(currency CurrencyCode, rule *FeeRule) *FeeSchedule {
+	fs.Rules[currency.Normalize()] = rule
+	return fs
+}
+
+//GetRule method - fee rule of the currency
+func (fs *FeeSchedule) GetRule(currency CurrencyCode) (*FeeRule, bool) {
+	rule, ok := fs.Rules[currency.Normalize()]
+	return rule, ok
+}
+
+//CalculateFee method - transfer fee of amount in currency
+func (fs *FeeSchedule) CalculateFee(amount Amount, currency CurrencyCode) (Amount, error) {
+	cur, ok := LookupCurrency(currency)
+	if !ok {
+		return Amount{}, fmt.Errorf(`currency "%s" is not supported`, currency)
+	}
+	rule, ok := fs.GetRule(cur.Code)
+	if !ok {
+		return Amount{}, fmt.Errorf(`fee rule of currency "%s" is not defined`, cur.Code)
+	}
+	return rule.calculate(amount, cur.Precision), nil
+}
+
+//TransferQuote struct - predicted transfer amounts
+type TransferQuote struct {
+	Currency  CurrencyCode       `json:"currency"`
+	FeeMode   TransactionFeeMode `json:"fee_mode"`
+	Amount    Amount             `json:"amount"`     //transferred amount
+	Fee       Amount             `json:"fee"`        //transfer fee
+	Total     Amount             `json:"total"`      //amount debited from the sender account
+	NetAmount Amount             `json:"net_amount"` //amount credited to the recipient account
+}
+
+//Verify method - cross-check quote against fee and total returned by FasaPay
+func (q *TransferQuote) Verify(transfer *CreateTransferResponseParams) error {
+	if !q.Fee.Equal(transfer.Fee) {
+		return fmt.Errorf(`fee mismatch: expected %s, actual %s`, q.Fee, transfer.Fee)
+	}
+	if !q.Total.Equal(transfer.Total) {
+		return fmt.Errorf(`total mismatch: expected %s, actual %s`, q.Total, transfer.Total)
+	}
+	return nil
+}
+
+//QuoteTransfer Predict fee, total debited from sender and net amount credited to recipient of the transfer.
+//With FiR fee mode (default) the fee is paid by the recipient, with FiS fee mode - by the sender.
+//DefaultFeeSchedule is used if schedule is nil. Transfers with the fee not less than the amount (FiR) are rejected.
+func QuoteTransfer(transfer *CreateTransferRequestParams, schedule *FeeSchedule) (*TransferQuote, error) {
+	if schedule == nil {
+		schedule = DefaultFeeSchedule()
+	}
+	fee, err := schedule.CalculateFee(transfer.Amount, transfer.Currency)
+	if err != nil {
+		return nil, err
+	}
+	quote := &TransferQuote{
+		Currency: transfer.Currency.Normalize(),
+		FeeMode:  transfer.FeeMode,
+		Amount:   transfer.Amount,
+		Fee:      fee,
+	}
+	switch transfer.FeeMode {
+	case TransactionFeeModeFiS:
+		quote.Total = transfer.Amount.Add(fee)
+		quote.NetAmount = transfer.Amount
+	case TransactionFeeModeFiR, "":
+		quote.FeeMode = TransactionFeeModeFiR
+		quote.Total = transfer.Amount
+		quote.NetAmount = transfer.Amount.Sub(fee)
+	default:
+		return nil, fmt.Errorf(`fee mode "%s" is not supported`, transfer.FeeMode)
+	}
+	if !quote.NetAmount.IsPositive() {
+		return nil, fmt.Errorf(`net amount %s %s is not positive, fee %s is not less than amount %s`, quote.NetAmount, quote.Currency, fee, transfer.Amount)
+	}
+	return quote, nil
+}
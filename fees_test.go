@@ -0,0 +1,123 @@
+package fasapay
+
+import (
+	"encoding/xml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type FeesTestSuite struct {
+	suite.Suite
+}
+
+func (suite *FeesTestSuite) TestDefaultFeeSchedule() {
+	schedule := DefaultFeeSchedule()
+	rule, ok := schedule.GetRule("idr")
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), MustParseAmount("0.5"), rule.Percent)
+	_, ok = schedule.GetRule(CurrencyCodeUSD)
+	assert.True(suite.T(), ok)
+	_, ok = schedule.GetRule("CHY")
+	assert.False(suite.T(), ok)
+}
+
+func (suite *FeesTestSuite) TestCalculateFee() {
+	schedule := DefaultFeeSchedule()
+	cases := []struct {
+		amount   string
+		currency CurrencyCode
+		expected string
+	}{
+		{"1000", CurrencyCodeIDR, "100.00"},
+		{"100000", CurrencyCodeIDR, "500.00"},
+		{"100000000", CurrencyCodeIDR, "50000.00"},
+		{"1", CurrencyCodeUSD, "0.01"},
+		{"100", CurrencyCodeUSD, "0.50"},
+		{"100.99", CurrencyCodeUSD, "0.50"},
+		{"101", CurrencyCodeUSD, "0.51"},
+		{"10000", CurrencyCodeUSD, "5.00"},
+	}
+	for _, c := range cases {
+		result, err := schedule.CalculateFee(MustParseAmount(c.amount), c.currency)
+		assert.NoError(suite.T(), err)
+		assert.Equal(suite.T(), c.expected, result.String(), c.amount)
+	}
+}
+
+func (suite *FeesTestSuite) TestCalculateFeeErrors() {
+	_, err := DefaultFeeSchedule().CalculateFee(MustParseAmount("1"), "CHY")
+	assert.Equal(suite.T(), `currency "CHY" is not supported`, err.Error())
+	_, err = NewFeeSchedule().CalculateFee(MustParseAmount("1"), CurrencyCodeUSD)
+	assert.Equal(suite.T(), `fee rule of currency "USD" is not defined`, err.Error())
+}
+
+func (suite *FeesTestSuite) TestQuoteTransferFiR() {
+	transfer := &CreateTransferRequestParams{To: "FP89680", Amount: MustParseAmount("1000"), Currency: CurrencyCodeIDR}
+	result, err := QuoteTransfer(transfer, nil)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), CurrencyCodeIDR, result.Currency)
+	assert.Equal(suite.T(), TransactionFeeModeFiR, result.FeeMode)
+	assert.Equal(suite.T(), "1000", result.Amount.String())
+	assert.Equal(suite.T(), "100.00", result.Fee.String())
+	assert.Equal(suite.T(), "1000", result.Total.String())
+	assert.Equal(suite.T(), "900.00", result.NetAmount.String())
+}
+
+func (suite *FeesTestSuite) TestQuoteTransferFiS() {
+	transfer := &CreateTransferRequestParams{To: "FP89680", Amount: MustParseAmount("1000"), Currency: CurrencyCodeIDR, FeeMode: TransactionFeeModeFiS}
+	result, err := QuoteTransfer(transfer, nil)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), TransactionFeeModeFiS, result.FeeMode)
+	assert.Equal(suite.T(), "100.00", result.Fee.String())
+	assert.Equal(suite.T(), "1100.00", result.Total.String())
+	assert.Equal(suite.T(), "1000", result.NetAmount.String())
+}
+
+func (suite *FeesTestSuite) TestQuoteTransferCustomSchedule() {
+	schedule := NewFeeSchedule().SetRule(CurrencyCodeUSD, &FeeRule{Percent: MustParseAmount("1")})
+	transfer := &CreateTransferRequestParams{Amount: MustParseAmount("10"), Currency: "usd", FeeMode: TransactionFeeModeFiS}
+	result, err := QuoteTransfer(transfer, schedule)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), CurrencyCodeUSD, result.Currency)
+	assert.Equal(suite.T(), "0.10", result.Fee.String())
+	assert.Equal(suite.T(), "10.10", result.Total.String())
+}
+
+func (suite *FeesTestSuite) TestQuoteTransferErrors() {
+	_, err := QuoteTransfer(&CreateTransferRequestParams{Amount: MustParseAmount("10"), Currency: "CHY"}, nil)
+	assert.Error(suite.T(), err)
+	_, err = QuoteTransfer(&CreateTransferRequestParams{Amount: MustParseAmount("10"), Currency: CurrencyCodeUSD, FeeMode: "foo"}, nil)
+	assert.Equal(suite.T(), `fee mode "foo" is not supported`, err.Error())
+}
+
+func (suite *FeesTestSuite) TestQuoteTransferFeeNotLessThanAmount() {
+	result, err := QuoteTransfer(&CreateTransferRequestParams{Amount: MustParseAmount("50"), Currency: CurrencyCodeIDR}, nil)
+	assert.Nil(suite.T(), result)
+	assert.Equal(suite.T(), `net amount -50.00 IDR is not positive, fee 100.00 is not less than amount 50`, err.Error())
+	_, err = QuoteTransfer(&CreateTransferRequestParams{Amount: MustParseAmount("100"), Currency: CurrencyCodeIDR}, nil)
+	assert.Error(suite.T(), err)
+	result, err = QuoteTransfer(&CreateTransferRequestParams{Amount: MustParseAmount("50"), Currency: CurrencyCodeIDR, FeeMode: TransactionFeeModeFiS}, nil)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "150.00", result.Total.String())
+}
+
+func (suite *FeesTestSuite) TestVerify() {
+	var response CreateTransferResponse
+	body, _ := LoadStubResponseData("stubs/transfers/transfer/success.xml")
+	_ = xml.Unmarshal(body, &response)
+
+	transfer := &CreateTransferRequestParams{To: "FP89680", Amount: MustParseAmount("1000"), Currency: CurrencyCodeIDR, FeeMode: TransactionFeeModeFiS}
+	quote, _ := QuoteTransfer(transfer, nil)
+	assert.NoError(suite.T(), quote.Verify(response.Transfers[0]))
+
+	quote.Fee = MustParseAmount("50")
+	assert.Equal(suite.T(), `fee mismatch: expected 50, actual 100`, quote.Verify(response.Transfers[0]).Error())
+	quote.Fee = MustParseAmount("100")
+	quote.Total = MustParseAmount("1000")
+	assert.Equal(suite.T(), `total mismatch: expected 1000, actual 1100.0`, quote.Verify(response.Transfers[0]).Error())
+}
+
+func TestFeesTestSuite(t *testing.T) {
+	suite.Run(t, new(FeesTestSuite))
+}
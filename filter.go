@@ -0,0 +1,172 @@
+package fasapay
+
+import (
+	"fmt"
+	"time"
+)
+
+//HistoryDateFormat format of history start/end dates (YYYY-mm-dd)
+const HistoryDateFormat = "2006-01-02"
+
+//HistoryOrderBy type
+type HistoryOrderBy string
+
+//HistoryOrderByDate const
+const HistoryOrderByDate HistoryOrderBy = "date"
+
+//HistoryOrderByAmount const
+const HistoryOrderByAmount HistoryOrderBy = "amount"
+
+//HistoryOrderByTo const
+const HistoryOrderByTo HistoryOrderBy = "to"
+
+//HistoryOrderByFrom const
+const HistoryOrderByFrom HistoryOrderBy = "from"
+
+//HistoryOrderByCurrency const
+const HistoryOrderByCurrency HistoryOrderBy = "currency"
+
+//HistoryOrderByBank const
+const HistoryOrderByBank HistoryOrderBy = "bank"
+
+//Validate method - check is order by parameter supported
+func (ob HistoryOrderBy) Validate() error {
+	switch ob {
+	case HistoryOrderByDate, HistoryOrderByAmount, HistoryOrderByTo, HistoryOrderByFrom, HistoryOrderByCurrency, HistoryOrderByBank:
+		return nil
+	}
+	return fmt.Errorf(`order by "%s" is not supported`, ob)
+}
+
+//SortOrder type
+type SortOrder string
+
+//SortOrderAsc const
+const SortOrderAsc SortOrder = "ASC"
+
+//SortOrderDesc const
+const SortOrderDesc SortOrder = "DESC"
+
+//Validate method - check is sort order supported
+func (so SortOrder) Validate() error {
+	switch so {
+	case SortOrderAsc, SortOrderDesc:
+		return nil
+	}
+	return fmt.Errorf(`sort order "%s" is not supported`, so)
+}
+
+//HistoryFilter struct - typed builder of history request params.
+//
+//    params, err := fasapay.NewHistoryFilter().
+//        Between(time.Now().AddDate(0, -1, 0), time.Now()).
+//        Type(fasapay.TransactionTypeTransfer).
+//        OrderBy(fasapay.HistoryOrderByAmount, fasapay.SortOrderDesc).
+//        Build()
+//
+//Dates are formatted in the location of given time values.
+type HistoryFilter struct {
+	params GetHistoryRequestParams
+}
+
+//NewHistoryFilter Create new empty history filter
+func NewHistoryFilter() *HistoryFilter {
+	return &HistoryFilter{}
+}
+
+//Between method - set start and end dates (both inclusive)
+func (f *HistoryFilter) Between(start time.Time, end time.Time) *HistoryFilter {
+	return f.Since(start).Until(end)
+}
+
+//Since method - set start date
+func (f *HistoryFilter) Since(start time.Time) *HistoryFilter {
+	f.params.StartDate = start.Format(HistoryDateFormat)
+	return f
+}
+
+//Until method - set end date
+func (f *HistoryFilter) Until(end time.Time) *HistoryFilter {
+	f.params.EndDate = end.Format(HistoryDateFormat)
+	return f
+}
+
+//Type method - set transaction type
+func (f *HistoryFilter) Type(transactionType TransactionType) *HistoryFilter {
+	f.params.Type = transactionType
+	return f
+}
+
+//OrderBy method - set sort parameter and order
+func (f *HistoryFilter) OrderBy(orderBy HistoryOrderBy, order SortOrder) *HistoryFilter {
+	f.params.OrderBy = orderBy
+	f.params.Order = order
+	return f
+}
+
+//Page method - set page number
+func (f *HistoryFilter) Page(page uint64) *HistoryFilter {
+	f.params.Page = page
+	return f
+}
+
+//PageSize method - set number of transactions per page (max 20)
+func (f *HistoryFilter) PageSize(pageSize uint64) *HistoryFilter {
+	f.params.PageSize = pageSize
+	return f
+}
+
+//Build method - validate the filter and create history request params
+func (f *HistoryFilter) Build() (*GetHistoryRequestParams, error) {
+	params := f.params
+	if err := params.isValid(); err != nil {
+		return nil, err
+	}
+	return &params, nil
+}
+
+//isValid method - check history request params before sending
+func (h *GetHistoryRequestParams) isValid() error {
+	start, err := parseHistoryDate("start_date", h.StartDate)
+	if err != nil {
+		return err
+	}
+	end, err := parseHistoryDate("end_date", h.EndDate)
+	if err != nil {
+		return err
+	}
+	if !start.IsZero() && !end.IsZero() && end.Before(start) {
+		return fmt.Errorf(`parameter "end_date" is before "start_date"`)
+	}
+	if h.Type != "" {
+		if err := h.Type.Validate(); err != nil {
+			return err
+		}
+	}
+	if h.OrderBy != "" {
+		if err := h.OrderBy.Validate(); err != nil {
+			return err
+		}
+	}
+	if h.Order != "" {
+		if err := h.Order.Validate(); err != nil {
+			return err
+		}
+	}
+	if h.PageSize > MaxHistoryPageSize {
+		return fmt.Errorf(`parameter "page_size" is greater than %d`, MaxHistoryPageSize)
+	}
+	return nil
+}
+
+//parseHistoryDate parse optional history date parameter
+func parseHistoryDate(name string, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	date, err := time.Parse(HistoryDateFormat, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf(`parameter "%s" is not valid, YYYY-mm-dd expected`, name)
+	}
+	return date, nil
+}
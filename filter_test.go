@@ -0,0 +1,96 @@
+package fasapay
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type HistoryFilterTestSuite struct {
+	suite.Suite
+}
+
+func (suite *HistoryFilterTestSuite) TestBuild() {
+	start := time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)
+	result, err := NewHistoryFilter().
+		Between(start, start.AddDate(0, 0, 27)).
+		Type(TransactionTypeTransfer).
+		OrderBy(HistoryOrderByAmount, SortOrderDesc).
+		Page(2).
+		PageSize(20).
+		Build()
+	assert.NoError(suite.T(), err)
+	expected := &GetHistoryRequestParams{
+		StartDate: "2022-03-01",
+		EndDate:   "2022-03-28",
+		Type:      TransactionTypeTransfer,
+		OrderBy:   HistoryOrderByAmount,
+		Order:     SortOrderDesc,
+		Page:      2,
+		PageSize:  20,
+	}
+	assert.Equal(suite.T(), expected, result)
+}
+
+func (suite *HistoryFilterTestSuite) TestBuildEmpty() {
+	result, err := NewHistoryFilter().Build()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), &GetHistoryRequestParams{}, result)
+}
+
+func (suite *HistoryFilterTestSuite) TestBuildSameDay() {
+	day := time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC)
+	result, err := NewHistoryFilter().Between(day.Add(23*time.Hour), day).Build()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "2022-03-01", result.StartDate)
+	assert.Equal(suite.T(), "2022-03-01", result.EndDate)
+}
+
+func (suite *HistoryFilterTestSuite) TestBuildLocation() {
+	wib := time.FixedZone("WIB", 7*60*60)
+	result, err := NewHistoryFilter().Since(time.Date(2022, 3, 1, 20, 0, 0, 0, time.UTC).In(wib)).Build()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "2022-03-02", result.StartDate)
+	assert.Equal(suite.T(), "", result.EndDate)
+}
+
+func (suite *HistoryFilterTestSuite) TestBuildEndBeforeStart() {
+	start := time.Date(2022, 3, 28, 0, 0, 0, 0, time.UTC)
+	result, err := NewHistoryFilter().Between(start, start.AddDate(0, 0, -1)).Build()
+	assert.Nil(suite.T(), result)
+	assert.Equal(suite.T(), `parameter "end_date" is before "start_date"`, err.Error())
+}
+
+func (suite *HistoryFilterTestSuite) TestBuildPageSizeTooBig() {
+	result, err := NewHistoryFilter().PageSize(MaxHistoryPageSize + 1).Build()
+	assert.Nil(suite.T(), result)
+	assert.Equal(suite.T(), `parameter "page_size" is greater than 20`, err.Error())
+}
+
+func (suite *HistoryFilterTestSuite) TestBuildInvalidEnums() {
+	_, err := NewHistoryFilter().Type("withdraw").Build()
+	assert.Equal(suite.T(), `transaction type "withdraw" is not supported`, err.Error())
+	_, err = NewHistoryFilter().OrderBy("note", SortOrderAsc).Build()
+	assert.Equal(suite.T(), `order by "note" is not supported`, err.Error())
+	_, err = NewHistoryFilter().OrderBy(HistoryOrderByDate, "asc").Build()
+	assert.Equal(suite.T(), `sort order "asc" is not supported`, err.Error())
+}
+
+func (suite *HistoryFilterTestSuite) TestIsValidDateFormat() {
+	params := &GetHistoryRequestParams{StartDate: "01.03.2022"}
+	assert.Equal(suite.T(), `parameter "start_date" is not valid, YYYY-mm-dd expected`, params.isValid().Error())
+	params = &GetHistoryRequestParams{StartDate: "2022-03-01", EndDate: "2022-02-30"}
+	assert.Equal(suite.T(), `parameter "end_date" is not valid, YYYY-mm-dd expected`, params.isValid().Error())
+	params = &GetHistoryRequestParams{StartDate: "2022-03-01", EndDate: "2022-03-01"}
+	assert.NoError(suite.T(), params.isValid())
+}
+
+func (suite *HistoryFilterTestSuite) TestTransactionTypeValidate() {
+	assert.NoError(suite.T(), TransactionTypeReceive.Validate())
+	assert.Error(suite.T(), TransactionType("").Validate())
+}
+
+func TestHistoryFilterTestSuite(t *testing.T) {
+	suite.Run(t, new(HistoryFilterTestSuite))
+}
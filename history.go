@@ -0,0 +1,266 @@
+package fasapay
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+//MaxHistoryPageSize max number of transactions per history page
+const MaxHistoryPageSize = 20
+
+//HistoryIterator struct - lazy iterator over transactions of all history pages.
+//
+//    it := client.Transfers().IterateHistory(params, ctx)
+//    for it.Next() {
+//        fmt.Println(it.Detail().BatchNumber)
+//    }
+//    if it.Err() != nil {
+//        panic(it.Err())
+//    }
+//
+type HistoryIterator struct {
+	resource *TransfersResource
+	ctx      context.Context
+	params   GetHistoryRequestParams
+	page     *GetHistoryResponsePageParams
+	details  []*GetHistoryResponseDetailParams
+	current  *GetHistoryResponseDetailParams
+	nextPage uint64
+	done     bool
+	err      error
+	options  *HistoryIteratorOptions
+	prefetch *historyPrefetch
+}
+
+//HistoryLimiter interface - rate limiter of history page requests (compatible with *rate.Limiter of golang.org/x/time/rate)
+type HistoryLimiter interface {
+	Wait(ctx context.Context) error
+}
+
+//IntervalLimiter struct - limiter allowing one request per interval
+type IntervalLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+//NewIntervalLimiter Create new limiter allowing one request per interval
+func NewIntervalLimiter(interval time.Duration) *IntervalLimiter {
+	return &IntervalLimiter{interval: interval}
+}
+
+//Wait method - wait for the next allowed request or return context error
+func (l *IntervalLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	delay := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+	if delay <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+//HistoryIteratorOptions struct
+type HistoryIteratorOptions struct {
+	Concurrency int            //max number of pages fetched ahead once page count is known, sequential fetching if less than 2
+	Limiter     HistoryLimiter //limiter of page requests, not limited if nil
+}
+
+//concurrency method
+func (o *HistoryIteratorOptions) concurrency() int {
+	if o == nil || o.Concurrency < 1 {
+		return 1
+	}
+	return o.Concurrency
+}
+
+//wait method - wait for the limiter
+func (o *HistoryIteratorOptions) wait(ctx context.Context) error {
+	if o == nil || o.Limiter == nil {
+		return nil
+	}
+	return o.Limiter.Wait(ctx)
+}
+
+//IterateHistory method - iterate transactions of all history pages starting from history.Page.
+//Page size is capped to MaxHistoryPageSize (max page size is used if it is not set).
+func (r *TransfersResource) IterateHistory(history *GetHistoryRequestParams, ctx context.Context) *HistoryIterator {
+	return r.IterateHistoryWithOptions(history, ctx, nil)
+}
+
+//IterateHistoryWithOptions method - iterate transactions of all history pages with concurrent prefetch of pages.
+//Once the first page returns page count, the remaining pages are fetched ahead with bounded concurrency
+//and delivered in page order. Close the iterator if iteration is stopped before the end.
+func (r *TransfersResource) IterateHistoryWithOptions(history *GetHistoryRequestParams, ctx context.Context, options *HistoryIteratorOptions) *HistoryIterator {
+	it := &HistoryIterator{resource: r, ctx: ctx, options: options}
+	if history != nil {
+		it.params = *history
+	}
+	if it.params.PageSize == 0 || it.params.PageSize > MaxHistoryPageSize {
+		it.params.PageSize = MaxHistoryPageSize
+	}
+	it.nextPage = it.params.Page
+	return it
+}
+
+//Next method - advance to the next transaction, false when all transactions are iterated or error occurred
+func (it *HistoryIterator) Next() bool {
+	for len(it.details) == 0 {
+		if it.done || it.err != nil {
+			it.current = nil
+			return false
+		}
+		it.fetch()
+	}
+	it.current = it.details[0]
+	it.details = it.details[1:]
+	return true
+}
+
+//Detail method - current transaction
+func (it *HistoryIterator) Detail() *GetHistoryResponseDetailParams {
+	return it.current
+}
+
+//Page method - pagination info of the last fetched page
+func (it *HistoryIterator) Page() *GetHistoryResponsePageParams {
+	return it.page
+}
+
+//Err method - error which stopped the iteration
+func (it *HistoryIterator) Err() error {
+	return it.err
+}
+
+//Close method - stop fetching pages ahead and wait for requests in flight
+func (it *HistoryIterator) Close() {
+	if it.prefetch != nil {
+		it.prefetch.cancel()
+		it.prefetch.wg.Wait()
+	}
+}
+
+//fetch method - fetch next history page
+func (it *HistoryIterator) fetch() {
+	var result *GetHistoryResponse
+	var err error
+	if it.prefetch != nil {
+		result, err = it.prefetch.next()
+	} else if err = it.options.wait(it.ctx); err == nil {
+		result, err = it.fetchPage(it.ctx, it.nextPage)
+	}
+	if err != nil {
+		it.err = err
+		it.Close()
+		return
+	}
+	it.done = true
+	if result.History == nil || len(result.History.Details) == 0 {
+		it.Close()
+		return
+	}
+	it.details = result.History.Details
+	it.page = result.History.Page
+	if it.prefetch != nil {
+		it.done = it.prefetch.isDone()
+	} else if it.page != nil {
+		it.nextPage = it.page.CurrentPage + 1
+		it.done = it.nextPage >= it.page.PageCount
+		if !it.done && it.options.concurrency() > 1 {
+			it.prefetch = it.startPrefetch(it.nextPage, it.page.PageCount)
+		}
+	}
+	if it.done {
+		it.Close()
+	}
+}
+
+//fetchPage method - fetch history page
+func (it *HistoryIterator) fetchPage(ctx context.Context, page uint64) (*GetHistoryResponse, error) {
+	params := it.params
+	params.Page = page
+	result, _, err := it.resource.GetHistory(&params, ctx, nil)
+	return result, err
+}
+
+//historyPage struct - fetched history page
+type historyPage struct {
+	result *GetHistoryResponse
+	err    error
+}
+
+//historyPrefetch struct - pages fetched ahead, at most window size pages are fetched or waiting for consumption
+type historyPrefetch struct {
+	ctx      context.Context
+	cancel   context.CancelFunc
+	pages    []chan historyPage
+	window   chan struct{}
+	position int
+	wg       sync.WaitGroup
+}
+
+//startPrefetch method - start fetching pages from first to pageCount-1 ahead
+func (it *HistoryIterator) startPrefetch(first uint64, pageCount uint64) *historyPrefetch {
+	ctx, cancel := context.WithCancel(it.ctx)
+	prefetch := &historyPrefetch{
+		ctx:    ctx,
+		cancel: cancel,
+		pages:  make([]chan historyPage, pageCount-first),
+		window: make(chan struct{}, it.options.concurrency()),
+	}
+	for i := range prefetch.pages {
+		prefetch.pages[i] = make(chan historyPage, 1)
+	}
+	prefetch.wg.Add(1)
+	go func() {
+		defer prefetch.wg.Done()
+		for i := range prefetch.pages {
+			select {
+			case prefetch.window <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			if err := it.options.wait(ctx); err != nil {
+				prefetch.pages[i] <- historyPage{err: err}
+				return
+			}
+			prefetch.wg.Add(1)
+			go func(i int) {
+				defer prefetch.wg.Done()
+				result, err := it.fetchPage(ctx, first+uint64(i))
+				prefetch.pages[i] <- historyPage{result: result, err: err}
+			}(i)
+		}
+	}()
+	return prefetch
+}
+
+//next method - wait for the next page in page order
+func (p *historyPrefetch) next() (*GetHistoryResponse, error) {
+	select {
+	case page := <-p.pages[p.position]:
+		p.position++
+		<-p.window
+		return page.result, page.err
+	case <-p.ctx.Done():
+		return nil, p.ctx.Err()
+	}
+}
+
+//isDone method - are all pages consumed
+func (p *historyPrefetch) isDone() bool {
+	return p.position >= len(p.pages)
+}
@@ -0,0 +1,252 @@
+package fasapay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type HistoryIteratorTestSuite struct {
+	suite.Suite
+	cfg      *Config
+	ctx      context.Context
+	testable *TransfersResource
+	mu       sync.Mutex
+	pages    []uint64
+	sizes    []uint64
+	inFlight int
+	maxFlow  int
+	delay    time.Duration
+}
+
+func (suite *HistoryIteratorTestSuite) SetupTest() {
+	cfg := BuildStubConfig()
+	transport := BuildStubHttpTransport()
+	suite.cfg = cfg
+	suite.ctx = context.Background()
+	suite.testable = &TransfersResource{ResourceAbstract: NewResourceAbstract(transport, cfg)}
+	suite.mu.Lock()
+	suite.pages = nil
+	suite.sizes = nil
+	suite.inFlight = 0
+	suite.maxFlow = 0
+	suite.delay = 0
+	suite.mu.Unlock()
+	httpmock.Activate()
+}
+
+func (suite *HistoryIteratorTestSuite) TearDownTest() {
+	httpmock.DeactivateAndReset()
+}
+
+//registerHistory method - respond with history of total items split into pages of requested size, fail on failed page
+func (suite *HistoryIteratorTestSuite) registerHistory(total uint64, failed int64) {
+	pageRegexp := regexp.MustCompile(`<page>([0-9]+)</page>`)
+	sizeRegexp := regexp.MustCompile(`<page_size>([0-9]+)</page_size>`)
+	errorBody, _ := LoadStubResponseData("stubs/transfers/history/error.xml")
+	httpmock.RegisterResponder(http.MethodPost, suite.cfg.Uri, func(req *http.Request) (*http.Response, error) {
+		body, _ := ioutil.ReadAll(req.Body)
+		var page, size uint64
+		if match := pageRegexp.FindStringSubmatch(string(body)); match != nil {
+			page, _ = strconv.ParseUint(match[1], 10, 64)
+		}
+		if match := sizeRegexp.FindStringSubmatch(string(body)); match != nil {
+			size, _ = strconv.ParseUint(match[1], 10, 64)
+		}
+		suite.mu.Lock()
+		suite.pages = append(suite.pages, page)
+		suite.sizes = append(suite.sizes, size)
+		suite.inFlight++
+		if suite.inFlight > suite.maxFlow {
+			suite.maxFlow = suite.inFlight
+		}
+		delay := suite.delay
+		suite.mu.Unlock()
+		time.Sleep(delay)
+		suite.mu.Lock()
+		suite.inFlight--
+		suite.mu.Unlock()
+		if int64(page) == failed {
+			return httpmock.NewBytesResponse(http.StatusOK, errorBody), nil
+		}
+		pageCount := (total + size - 1) / size
+		var details strings.Builder
+		for item := page * size; item < (page+1)*size && item < total; item++ {
+			fmt.Fprintf(&details, `<detail><batchnumber>TR%d</batchnumber><amount>1</amount><fee>0</fee><status>FINISH</status></detail>`, item)
+		}
+		return httpmock.NewStringResponse(http.StatusOK, fmt.Sprintf(`<fasa_response id="1" date_time="2011-08-03T10:34:34+07:00"><history><page><total_item>%d</total_item><page_count>%d</page_count><current_page>%d</current_page></page>%s</history></fasa_response>`, total, pageCount, page, details.String())), nil
+	})
+}
+
+//collect method - iterate all batch numbers
+func (suite *HistoryIteratorTestSuite) collect(it *HistoryIterator) []string {
+	var result []string
+	for it.Next() {
+		result = append(result, it.Detail().BatchNumber)
+	}
+	return result
+}
+
+//expected method - batch numbers of items range
+func (suite *HistoryIteratorTestSuite) expected(from int, to int) []string {
+	var result []string
+	for item := from; item < to; item++ {
+		result = append(result, fmt.Sprintf("TR%d", item))
+	}
+	return result
+}
+
+func (suite *HistoryIteratorTestSuite) TestIterateAllPages() {
+	suite.registerHistory(45, -1)
+	it := suite.testable.IterateHistory(nil, suite.ctx)
+	assert.Equal(suite.T(), suite.expected(0, 45), suite.collect(it))
+	assert.NoError(suite.T(), it.Err())
+	assert.Nil(suite.T(), it.Detail())
+	assert.Equal(suite.T(), []uint64{0, 1, 2}, suite.pages)
+	assert.Equal(suite.T(), []uint64{20, 20, 20}, suite.sizes)
+	assert.Equal(suite.T(), uint64(2), it.Page().CurrentPage)
+	assert.Equal(suite.T(), uint64(3), it.Page().PageCount)
+	assert.False(suite.T(), it.Next())
+}
+
+func (suite *HistoryIteratorTestSuite) TestIteratePageSizeCap() {
+	suite.registerHistory(10, -1)
+	it := suite.testable.IterateHistory(&GetHistoryRequestParams{PageSize: 100}, suite.ctx)
+	assert.Len(suite.T(), suite.collect(it), 10)
+	assert.Equal(suite.T(), []uint64{20}, suite.sizes)
+}
+
+func (suite *HistoryIteratorTestSuite) TestIterateFromPage() {
+	suite.registerHistory(25, -1)
+	params := &GetHistoryRequestParams{Page: 2, PageSize: 5, Type: TransactionTypeTransfer}
+	it := suite.testable.IterateHistory(params, suite.ctx)
+	assert.Equal(suite.T(), suite.expected(10, 25), suite.collect(it))
+	assert.Equal(suite.T(), []uint64{2, 3, 4}, suite.pages)
+	assert.Equal(suite.T(), uint64(2), params.Page)
+}
+
+func (suite *HistoryIteratorTestSuite) TestIterateEmpty() {
+	suite.registerHistory(0, -1)
+	it := suite.testable.IterateHistory(nil, suite.ctx)
+	assert.False(suite.T(), it.Next())
+	assert.NoError(suite.T(), it.Err())
+	assert.Len(suite.T(), suite.pages, 1)
+}
+
+func (suite *HistoryIteratorTestSuite) TestIterateError() {
+	suite.registerHistory(45, 1)
+	it := suite.testable.IterateHistory(nil, suite.ctx)
+	assert.Equal(suite.T(), suite.expected(0, 20), suite.collect(it))
+	var apiErr *APIError
+	assert.True(suite.T(), errors.As(it.Err(), &apiErr))
+	assert.False(suite.T(), it.Next())
+	assert.Equal(suite.T(), []uint64{0, 1}, suite.pages)
+}
+
+func (suite *HistoryIteratorTestSuite) TestPrefetchInPageOrder() {
+	suite.registerHistory(200, -1)
+	suite.mu.Lock()
+	suite.delay = 5 * time.Millisecond
+	suite.mu.Unlock()
+	options := &HistoryIteratorOptions{Concurrency: 3}
+	it := suite.testable.IterateHistoryWithOptions(&GetHistoryRequestParams{PageSize: 10}, suite.ctx, options)
+	assert.Equal(suite.T(), suite.expected(0, 200), suite.collect(it))
+	assert.NoError(suite.T(), it.Err())
+	assert.Len(suite.T(), suite.pages, 20)
+	assert.True(suite.T(), suite.maxFlow > 1)
+	assert.True(suite.T(), suite.maxFlow <= 3)
+}
+
+func (suite *HistoryIteratorTestSuite) TestPrefetchLimiter() {
+	suite.registerHistory(50, -1)
+	limiter := &stubHistoryLimiter{}
+	options := &HistoryIteratorOptions{Concurrency: 2, Limiter: limiter}
+	it := suite.testable.IterateHistoryWithOptions(&GetHistoryRequestParams{PageSize: 10}, suite.ctx, options)
+	assert.Len(suite.T(), suite.collect(it), 50)
+	assert.Equal(suite.T(), 5, limiter.calls())
+}
+
+func (suite *HistoryIteratorTestSuite) TestPrefetchLimiterError() {
+	suite.registerHistory(50, -1)
+	limiter := &stubHistoryLimiter{failAfter: 2}
+	options := &HistoryIteratorOptions{Concurrency: 2, Limiter: limiter}
+	it := suite.testable.IterateHistoryWithOptions(&GetHistoryRequestParams{PageSize: 10}, suite.ctx, options)
+	assert.Equal(suite.T(), suite.expected(0, 20), suite.collect(it))
+	assert.Equal(suite.T(), "limiter error", it.Err().Error())
+}
+
+func (suite *HistoryIteratorTestSuite) TestPrefetchError() {
+	suite.registerHistory(50, 3)
+	options := &HistoryIteratorOptions{Concurrency: 2}
+	it := suite.testable.IterateHistoryWithOptions(&GetHistoryRequestParams{PageSize: 10}, suite.ctx, options)
+	assert.Equal(suite.T(), suite.expected(0, 30), suite.collect(it))
+	var apiErr *APIError
+	assert.True(suite.T(), errors.As(it.Err(), &apiErr))
+	assert.False(suite.T(), it.Next())
+}
+
+func (suite *HistoryIteratorTestSuite) TestPrefetchClose() {
+	suite.registerHistory(200, -1)
+	options := &HistoryIteratorOptions{Concurrency: 2}
+	it := suite.testable.IterateHistoryWithOptions(&GetHistoryRequestParams{PageSize: 10}, suite.ctx, options)
+	for i := 0; i < 15 && it.Next(); i++ {
+	}
+	it.Close()
+	assert.NoError(suite.T(), it.Err())
+	for it.Next() {
+	}
+	assert.True(suite.T(), errors.Is(it.Err(), context.Canceled))
+	suite.mu.Lock()
+	defer suite.mu.Unlock()
+	assert.True(suite.T(), len(suite.pages) < 20)
+}
+
+func (suite *HistoryIteratorTestSuite) TestIntervalLimiter() {
+	limiter := NewIntervalLimiter(20 * time.Millisecond)
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		assert.NoError(suite.T(), limiter.Wait(suite.ctx))
+	}
+	assert.True(suite.T(), time.Since(start) >= 40*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(suite.ctx)
+	cancel()
+	assert.Equal(suite.T(), context.Canceled, limiter.Wait(ctx))
+}
+
+func TestHistoryIteratorTestSuite(t *testing.T) {
+	suite.Run(t, new(HistoryIteratorTestSuite))
+}
+
+type stubHistoryLimiter struct {
+	mu        sync.Mutex
+	count     int
+	failAfter int
+}
+
+func (l *stubHistoryLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.count++
+	if l.failAfter > 0 && l.count > l.failAfter {
+		return fmt.Errorf("limiter error")
+	}
+	return nil
+}
+
+func (l *stubHistoryLimiter) calls() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.count
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/xml"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"net/url"
 	"time"
@@ -18,7 +19,12 @@ type RequestBuilder struct {
 
 //BuildUri method
 func (rb *RequestBuilder) buildUri() (uri *url.URL, err error) {
-	u, err := url.Parse(rb.cfg.Uri)
+	return rb.buildEndpointUri(rb.cfg.Uri)
+}
+
+//BuildEndpointUri method
+func (rb *RequestBuilder) buildEndpointUri(endpoint string) (uri *url.URL, err error) {
+	u, err := url.Parse(endpoint)
 	if err != nil {
 		return nil, fmt.Errorf("RequestBuilder.buildUri parse: %v", err)
 	}
@@ -39,10 +45,15 @@ func (rb *RequestBuilder) buildHeaders() http.Header {
 
 //BuildRequest method
 func (rb *RequestBuilder) buildRequest(ctx context.Context, body []byte) (req *http.Request, err error) {
+	return rb.buildEndpointRequest(ctx, rb.cfg.Uri, body)
+}
+
+//BuildEndpointRequest method
+func (rb *RequestBuilder) buildEndpointRequest(ctx context.Context, endpoint string, body []byte) (req *http.Request, err error) {
 	//build body
 	bodyReader, err := rb.buildBody(body)
 	//build uri
-	uri, err := rb.buildUri()
+	uri, err := rb.buildEndpointUri(endpoint)
 	if err != nil {
 		return nil, fmt.Errorf("RequestBuilder.buildRequest build uri: %v", err)
 	}
@@ -59,22 +70,94 @@ func (rb *RequestBuilder) buildRequest(ctx context.Context, body []byte) (req *h
 //NewHttpTransport create new http transport
 func NewHttpTransport(config *Config, h *http.Client) *Transport {
 	rb := &RequestBuilder{cfg: config}
-	return &Transport{http: h, rb: rb}
+	return &Transport{http: h, rb: rb, pool: newEndpointPoolFromConfig(config)}
 }
 
 //Transport wrapper
 type Transport struct {
-	http *http.Client
-	rb   *RequestBuilder
+	http        *http.Client
+	rb          *RequestBuilder
+	retry       *RetryPolicy
+	pool        *EndpointPool
+	onEndpoint  EndpointHook
+	middlewares []Middleware
+	logger      Logger
+	metrics     MetricsSink
+	tracer      Tracer
 }
 
 //SendRequest Send request method
 func (tr *Transport) SendRequest(ctx context.Context, body []byte) (resp *http.Response, err error) {
-	req, err := tr.rb.buildRequest(ctx, body)
-	if err != nil {
-		return nil, fmt.Errorf("transport.SendRequest: %v", err)
+	resp, _, err = tr.sendRequest(ctx, body, false)
+	return resp, err
+}
+
+//sendRequest Send request with endpoints failover, returns the endpoint of the last attempt.
+//Idempotent requests fail over to the next endpoint on connection errors or 5xx status codes,
+//non-idempotent requests only when the connection could not be established.
+func (tr *Transport) sendRequest(ctx context.Context, body []byte, idempotent bool) (resp *http.Response, endpoint string, err error) {
+	endpoints := tr.pool.candidates()
+	for i := range endpoints {
+		endpoint = endpoints[i]
+		var req *http.Request
+		req, err = tr.rb.buildEndpointRequest(ctx, endpoint, body)
+		if err != nil {
+			return nil, endpoint, fmt.Errorf("transport.SendRequest: %v", err)
+		}
+		resp, err = tr.http.Do(req)
+		if tr.onEndpoint != nil {
+			tr.onEndpoint(endpoint, resp, err)
+		}
+		if ctx.Err() != nil {
+			return resp, endpoint, err
+		}
+		if !isEndpointFailure(resp, err) {
+			tr.pool.MarkSuccess(endpoint)
+			return resp, endpoint, err
+		}
+		tr.pool.MarkFailure(endpoint)
+		if i == len(endpoints)-1 || !(idempotent || isDialError(err)) {
+			return resp, endpoint, err
+		}
+		if err == nil {
+			_, _ = io.Copy(ioutil.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+	}
+	return nil, endpoint, fmt.Errorf("transport.SendRequest: no endpoints available")
+}
+
+//buildMiddlewares method - built-in middlewares followed by user defined ones
+func (tr *Transport) buildMiddlewares() []Middleware {
+	var middlewares []Middleware
+	if tr.tracer != nil {
+		middlewares = append(middlewares, TracingMiddleware(tr.tracer))
+	}
+	if tr.metrics != nil {
+		middlewares = append(middlewares, MetricsMiddleware(tr.metrics))
+	}
+	if tr.logger != nil {
+		middlewares = append(middlewares, LoggingMiddleware(tr.logger))
+	}
+	return append(middlewares, tr.middlewares...)
+}
+
+//sendRequestWithRetry Send request with retry policy (idempotent operations only), returns the endpoint of the last attempt
+func (tr *Transport) sendRequestWithRetry(ctx context.Context, body []byte) (resp *http.Response, endpoint string, err error) {
+	attempts := tr.retry.attempts()
+	for attempt := 1; ; attempt++ {
+		resp, endpoint, err = tr.sendRequest(ctx, body, true)
+		if attempt >= attempts || !tr.retry.isRetryable(resp, err) {
+			return resp, endpoint, err
+		}
+		if err == nil {
+			_, _ = io.Copy(ioutil.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+		if waitErr := tr.retry.wait(ctx, attempt); waitErr != nil {
+			return nil, endpoint, fmt.Errorf("transport.sendRequestWithRetry: %w", waitErr)
+		}
 	}
-	return tr.http.Do(req)
 }
 
 //RequestParamsAttributes struct
@@ -90,6 +173,17 @@ type RequestParams struct {
 	Auth    *RequestAuthParams `xml:"auth" json:"auth"`
 }
 
+//requestParamsInterface interface
+type requestParamsInterface interface {
+	getRequestId() string
+	getItemsCount() int
+}
+
+//getRequestId method
+func (r RequestParams) getRequestId() string {
+	return r.Id
+}
+
 //RequestAuthParams struct
 type RequestAuthParams struct {
 	XMLName xml.Name `xml:"auth" json:"-"`
@@ -110,36 +204,20 @@ func (r *ResponseBody) IsSuccess() bool {
 	return r.Errors == nil
 }
 
-//GetError method
+//GetError method - documented message of the error code (unexpected error message if code is unknown)
 func (r *ResponseBody) GetError() string {
-	var message string
-	switch r.Errors.Code {
-	case ErrorCodeNotValidXmlRequest:
-		message = ErrorMessageNotValidXmlRequest
-		break
-	case ErrorCodeUnauthorized:
-		message = ErrorMessageUnauthorized
-		break
-	case ErrorCodeNotAcceptableTransfer:
-		message = ErrorMessageNotAcceptableTransfer
-		break
-	case ErrorCodeDetailRequestError:
-		message = ErrorMessageDetailRequestError
-		break
-	case ErrorCodeHistoryRequestError:
-		message = ErrorMessageHistoryRequestError
-		break
-	case ErrorCodeBalanceRequestError:
-		message = ErrorMessageBalanceRequestError
-		break
-	case ErrorCodeAccountRequestError:
-		message = ErrorMessageAccountRequestError
-		break
-	default:
-		message = ErrorMessageUnexpectedError
-		break
+	if codeErr, ok := LookupErrorCode(r.Errors.Code); ok {
+		return codeErr.Message
+	}
+	return ErrorMessageUnexpectedError
+}
+
+//GetAPIError method
+func (r *ResponseBody) GetAPIError() *APIError {
+	if r.IsSuccess() {
+		return nil
 	}
-	return message
+	return newAPIError(r.Errors, r.GetError())
 }
 
 //ResponseBodyErrors struct
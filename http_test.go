@@ -2,12 +2,15 @@ package fasapay
 
 import (
 	"context"
+	"errors"
 	"github.com/jarcoal/httpmock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"testing"
+	"time"
 )
 
 type HttpRequestBuilderTestSuite struct {
@@ -77,6 +80,18 @@ func (suite *HttpResponseBodyTestSuite) TestGetError() {
 	assert.Equal(suite.T(), ErrorMessageAccountRequestError, rsp.GetError())
 }
 
+func (suite *HttpResponseBodyTestSuite) TestGetAPIError() {
+	rsp := &ResponseBody{}
+	assert.Nil(suite.T(), rsp.GetAPIError())
+	rsp.Errors = &ResponseBodyErrors{Id: "tid3", Mode: "transfer", Code: ErrorCodeNotAcceptableTransfer, Data: []*ResponseBodyErrorParams{{Code: 40601}}}
+	result := rsp.GetAPIError()
+	assert.Equal(suite.T(), "tid3", result.Id)
+	assert.Equal(suite.T(), "transfer", result.Mode)
+	assert.Equal(suite.T(), ErrorCodeNotAcceptableTransfer, result.Code)
+	assert.Equal(suite.T(), ErrorMessageNotAcceptableTransfer, result.Message)
+	assert.Equal(suite.T(), uint64(40601), result.Errors[0].Code)
+}
+
 func TestHttpResponseBodyTestSuite(t *testing.T) {
 	suite.Run(t, new(HttpResponseBodyTestSuite))
 }
@@ -109,6 +124,137 @@ func (suite *HttpTransportTestSuite) TestSendRequest() {
 	assert.Equal(suite.T(), body, bodyRsp)
 }
 
+func (suite *HttpTransportTestSuite) TestSendRequestWithRetrySuccess() {
+	body, _ := LoadStubResponseData("stubs/accounts/balances/success.xml")
+	responses := []*http.Response{
+		httpmock.NewStringResponse(http.StatusServiceUnavailable, "Service unavailable"),
+		httpmock.NewStringResponse(http.StatusBadGateway, "Bad gateway"),
+		httpmock.NewBytesResponse(http.StatusOK, body),
+	}
+	calls := 0
+	httpmock.RegisterResponder(http.MethodPost, suite.cfg.Uri, func(req *http.Request) (*http.Response, error) {
+		rsp := responses[calls]
+		calls++
+		return rsp, nil
+	})
+	suite.testable.retry = &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}
+
+	resp, _, err := suite.testable.sendRequestWithRetry(suite.ctx, nil)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+	assert.Equal(suite.T(), 3, calls)
+}
+
+func (suite *HttpTransportTestSuite) TestSendRequestWithRetryAttemptsExceeded() {
+	calls := 0
+	httpmock.RegisterResponder(http.MethodPost, suite.cfg.Uri, func(req *http.Request) (*http.Response, error) {
+		calls++
+		return httpmock.NewStringResponse(http.StatusServiceUnavailable, "Service unavailable"), nil
+	})
+	suite.testable.retry = &RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}
+
+	resp, _, err := suite.testable.sendRequestWithRetry(suite.ctx, nil)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(suite.T(), 2, calls)
+}
+
+func (suite *HttpTransportTestSuite) TestSendRequestWithRetryNotRetryable() {
+	calls := 0
+	httpmock.RegisterResponder(http.MethodPost, suite.cfg.Uri, func(req *http.Request) (*http.Response, error) {
+		calls++
+		return httpmock.NewStringResponse(http.StatusBadRequest, "Bad request"), nil
+	})
+	suite.testable.retry = &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}
+
+	resp, _, err := suite.testable.sendRequestWithRetry(suite.ctx, nil)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(suite.T(), 1, calls)
+}
+
+func (suite *HttpTransportTestSuite) TestSendRequestWithRetryNetworkError() {
+	calls := 0
+	httpmock.RegisterResponder(http.MethodPost, suite.cfg.Uri, func(req *http.Request) (*http.Response, error) {
+		calls++
+		return nil, errors.New("connection reset by peer")
+	})
+	suite.testable.retry = &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}
+
+	resp, _, err := suite.testable.sendRequestWithRetry(suite.ctx, nil)
+	assert.Error(suite.T(), err)
+	assert.Nil(suite.T(), resp)
+	assert.Equal(suite.T(), 3, calls)
+}
+
+func (suite *HttpTransportTestSuite) TestSendRequestWithRetryContextCanceled() {
+	ctx, cancel := context.WithCancel(suite.ctx)
+	calls := 0
+	httpmock.RegisterResponder(http.MethodPost, suite.cfg.Uri, func(req *http.Request) (*http.Response, error) {
+		calls++
+		cancel()
+		return httpmock.NewStringResponse(http.StatusServiceUnavailable, "Service unavailable"), nil
+	})
+	suite.testable.retry = &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Second}
+
+	resp, _, err := suite.testable.sendRequestWithRetry(ctx, nil)
+	assert.Error(suite.T(), err)
+	assert.True(suite.T(), errors.Is(err, context.Canceled))
+	assert.Nil(suite.T(), resp)
+	assert.Equal(suite.T(), 1, calls)
+}
+
+func (suite *HttpTransportTestSuite) TestSendRequestFailover() {
+	body, _ := LoadStubResponseData("stubs/accounts/balances/success.xml")
+	httpmock.RegisterResponder(http.MethodPost, ProdAPIUrl, httpmock.NewStringResponder(http.StatusBadGateway, "Bad gateway"))
+	httpmock.RegisterResponder(http.MethodPost, ProdAPIUrlSecond, httpmock.NewBytesResponder(http.StatusOK, body))
+	var endpoints []string
+	suite.testable = NewHttpTransport(NewConfig("foo", "bar"), &http.Client{})
+	suite.testable.onEndpoint = func(endpoint string, resp *http.Response, err error) {
+		endpoints = append(endpoints, endpoint)
+	}
+
+	resp, endpoint, err := suite.testable.sendRequest(suite.ctx, nil, true)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+	assert.Equal(suite.T(), ProdAPIUrlSecond, endpoint)
+	assert.Equal(suite.T(), []string{ProdAPIUrl, ProdAPIUrlSecond}, endpoints)
+	assert.False(suite.T(), suite.testable.pool.IsHealthy(ProdAPIUrl))
+	assert.True(suite.T(), suite.testable.pool.IsHealthy(ProdAPIUrlSecond))
+
+	//the failed endpoint is skipped while in cool-down
+	endpoints = nil
+	_, _, err = suite.testable.sendRequest(suite.ctx, nil, true)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []string{ProdAPIUrlSecond}, endpoints)
+}
+
+func (suite *HttpTransportTestSuite) TestSendRequestNonIdempotentNoFailover() {
+	httpmock.RegisterResponder(http.MethodPost, ProdAPIUrl, httpmock.NewStringResponder(http.StatusBadGateway, "Bad gateway"))
+	var endpoints []string
+	suite.testable = NewHttpTransport(NewConfig("foo", "bar"), &http.Client{})
+	suite.testable.onEndpoint = func(endpoint string, resp *http.Response, err error) {
+		endpoints = append(endpoints, endpoint)
+	}
+
+	resp, err := suite.testable.SendRequest(suite.ctx, nil)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusBadGateway, resp.StatusCode)
+	assert.Equal(suite.T(), []string{ProdAPIUrl}, endpoints)
+	assert.False(suite.T(), suite.testable.pool.IsHealthy(ProdAPIUrl))
+}
+
+func (suite *HttpTransportTestSuite) TestSendRequestNonIdempotentDialErrorFailover() {
+	body, _ := LoadStubResponseData("stubs/transfers/transfer/success.xml")
+	httpmock.RegisterResponder(http.MethodPost, ProdAPIUrl, httpmock.NewErrorResponder(&net.OpError{Op: "dial", Err: errors.New("connection refused")}))
+	httpmock.RegisterResponder(http.MethodPost, ProdAPIUrlSecond, httpmock.NewBytesResponder(http.StatusOK, body))
+	suite.testable = NewHttpTransport(NewConfig("foo", "bar"), &http.Client{})
+
+	resp, err := suite.testable.SendRequest(suite.ctx, nil)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+}
+
 func (suite *HttpTransportTestSuite) TearDownTest() {
 	httpmock.DeactivateAndReset()
 }
@@ -0,0 +1,268 @@
+package fasapay
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+//newTransferRef Generate random transfer reference code
+func newTransferRef() (string, error) {
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", fmt.Errorf("newTransferRef error: %w", err)
+	}
+	return hex.EncodeToString(bytes), nil
+}
+
+//newTransferRefFromId Derive stable reference code from transfer id
+func newTransferRefFromId(id string) string {
+	sum := sha256.Sum256([]byte(id))
+	return hex.EncodeToString(sum[:16])
+}
+
+//assignTransferRefs Assign reference code (and id) to transfers without it.
+//Reference code of transfer with id is derived from the id, so params rebuilt with the same id are recognized as retry.
+//Random reference code of transfer without id is kept in the params, so only submitting the same params again is recognized as retry.
+func assignTransferRefs(transfers []*CreateTransferRequestParams) error {
+	for _, transfer := range transfers {
+		if transfer.Ref == "" && transfer.Id != "" {
+			transfer.Ref = newTransferRefFromId(transfer.Id)
+		}
+		if transfer.Ref == "" {
+			ref, err := newTransferRef()
+			if err != nil {
+				return err
+			}
+			transfer.Ref = ref
+		}
+		if transfer.Id == "" {
+			transfer.Id = transfer.Ref
+		}
+	}
+	return nil
+}
+
+//isSameTransfer Check is stored transfer the same as submitted one
+func isSameTransfer(stored *CreateTransferRequestParams, transfer *CreateTransferRequestParams) bool {
+	return stored == nil || (stored.To == transfer.To && stored.Currency == transfer.Currency && stored.Amount.Equal(transfer.Amount))
+}
+
+//newTransferResultFromDetail Build transfer result from the transaction details
+func newTransferResultFromDetail(detail *GetDetailsResponseDetailParams) *CreateTransferResponseParams {
+	return &CreateTransferResponseParams{
+		Mode:        detail.Mode,
+		Code:        detail.Code,
+		BatchNumber: detail.BatchNumber,
+		Date:        detail.Date,
+		Time:        detail.Time,
+		From:        detail.From,
+		To:          detail.To,
+		Fee:         detail.Fee,
+		Amount:      detail.Amount,
+		Total:       detail.Total,
+		FeeMode:     detail.FeeMode,
+		Currency:    detail.Currency,
+		Note:        detail.Note,
+		Status:      detail.Status,
+		Type:        detail.Type,
+		Method:      detail.Method,
+	}
+}
+
+//createTransferIdempotent method - submit transfers which were not accepted by FasaPay yet recording their lifecycle in the store.
+//Before each retry the transfers with unknown outcome are looked up by reference code.
+func (r *TransfersResource) createTransferIdempotent(transfers []*CreateTransferRequestParams, ctx context.Context, attributes *RequestParamsAttributes) (*CreateTransferResponse, *http.Response, error) {
+	err := assignTransferRefs(transfers)
+	if err != nil {
+		return nil, nil, err
+	}
+	results := make([]*CreateTransferResponseParams, len(transfers))
+	policy := r.tr.retry
+	for attempt := 1; ; attempt++ {
+		pending, err := r.reconcileTransfers(transfers, results, ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(pending) == 0 {
+			return &CreateTransferResponse{Transfers: acceptedTransfers(results), Results: buildTransferResults(transfers, results, nil, nil)}, nil, nil
+		}
+		submit := make([]*CreateTransferRequestParams, len(pending))
+		for i, index := range pending {
+			submit[i] = transfers[index]
+			if err := r.store.MarkSubmitted(transfers[index].Ref); err != nil {
+				return nil, nil, err
+			}
+		}
+		requestParams := &CreateTransferRequest{r.buildRequestParams(attributes), submit}
+		var result CreateTransferResponse
+		rsp, err := r.execute(ctx, OperationCreateTransfer, requestParams, &result)
+		if err != nil {
+			if attempt >= policy.attempts() || !isRetryableError(policy, rsp, err) {
+				return nil, rsp, err
+			}
+			if waitErr := policy.wait(ctx, attempt); waitErr != nil {
+				return nil, rsp, waitErr
+			}
+			continue
+		}
+		correlated := correlateTransferResults(submit, &result)
+		for i, index := range pending {
+			if err := r.recordTransferResult(correlated[i]); err != nil {
+				return nil, rsp, err
+			}
+			if correlated[i].IsSuccess() {
+				results[index] = correlated[i].Result
+			}
+		}
+		result.Results = buildTransferResults(transfers, results, pending, correlated)
+		if !result.IsSuccess() {
+			return &result, rsp, result.GetAPIError()
+		}
+		result.Transfers = acceptedTransfers(results)
+		return &result, rsp, nil
+	}
+}
+
+//recordTransferResult method - save outcome of the submitted transfer into the store.
+//Not processed transfers (missing in the response) keep submitted state, because their outcome is unknown,
+//so they are looked up by reference code before the next submission.
+func (r *TransfersResource) recordTransferResult(result *TransferResult) error {
+	switch result.Status {
+	case TransferResultStatusAccepted:
+		return r.store.MarkFinished(result.Transfer.Ref, result.Result)
+	case TransferResultStatusRejected:
+		return r.store.MarkFailed(result.Transfer.Ref, result.Error.Error())
+	}
+	return nil
+}
+
+//acceptedTransfers Collect results of accepted transfers in the input order, transfers without result are skipped
+//(use CreateTransferResponse.Results for the per transfer mapping)
+func acceptedTransfers(results []*CreateTransferResponseParams) []*CreateTransferResponseParams {
+	accepted := make([]*CreateTransferResponseParams, 0, len(results))
+	for _, result := range results {
+		if result != nil {
+			accepted = append(accepted, result)
+		}
+	}
+	return accepted
+}
+
+//buildTransferResults Build result of every transfer from already accepted ones and correlated results of the submitted ones
+func buildTransferResults(transfers []*CreateTransferRequestParams, accepted []*CreateTransferResponseParams, submitted []int, correlated []*TransferResult) []*TransferResult {
+	results := make([]*TransferResult, len(transfers))
+	for i, index := range submitted {
+		results[index] = correlated[i]
+	}
+	for index, transfer := range transfers {
+		if results[index] == nil {
+			results[index] = &TransferResult{Id: transfer.Id, Status: TransferResultStatusNotProcessed, Transfer: transfer}
+			if accepted[index] != nil {
+				results[index].Status = TransferResultStatusAccepted
+				results[index].Result = accepted[index]
+			}
+		}
+	}
+	return results
+}
+
+//reconcileTransfers method - collect results of already accepted transfers, return indexes of transfers to submit
+func (r *TransfersResource) reconcileTransfers(transfers []*CreateTransferRequestParams, results []*CreateTransferResponseParams, ctx context.Context) ([]int, error) {
+	var pending []int
+	for index, transfer := range transfers {
+		if results[index] != nil {
+			continue
+		}
+		record, ok, err := r.store.Load(transfer.Ref)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			if err := r.store.SavePending(&TransferRecord{Ref: transfer.Ref, Transfer: transfer}); err != nil {
+				return nil, err
+			}
+			pending = append(pending, index)
+			continue
+		}
+		if !isSameTransfer(record.Transfer, transfer) {
+			return nil, fmt.Errorf(`transfer ref "%s" is already used by another transfer`, transfer.Ref)
+		}
+		switch record.State {
+		case TransferStateFinished:
+			results[index] = record.Result
+			continue
+		case TransferStatePending, TransferStateFailed:
+			pending = append(pending, index)
+			continue
+		}
+		detail, err := r.lookupTransfer(transfer.Ref, ctx)
+		if err != nil {
+			return nil, err
+		}
+		if detail == nil {
+			pending = append(pending, index)
+			continue
+		}
+		results[index] = newTransferResultFromDetail(detail)
+		if err := r.store.MarkFinished(transfer.Ref, results[index]); err != nil {
+			return nil, err
+		}
+	}
+	return pending, nil
+}
+
+//ResumeTransfers method - resume transfers stuck in the store (e.g. after process restart) not updated for the given duration.
+//Submitted transfers are looked up by reference code, the ones not found in FasaPay and never submitted ones are submitted again.
+func (r *TransfersResource) ResumeTransfers(olderThan time.Duration, ctx context.Context, attributes *RequestParamsAttributes) (*CreateTransferResponse, *http.Response, error) {
+	if r.store == nil {
+		return nil, nil, fmt.Errorf("TransfersResource.ResumeTransfers error: transfer store is not set")
+	}
+	records, err := r.store.ListStuck(olderThan)
+	if err != nil {
+		return nil, nil, fmt.Errorf("TransfersResource.ResumeTransfers error: %w", err)
+	}
+	transfers := make([]*CreateTransferRequestParams, len(records))
+	for i, record := range records {
+		transfers[i] = record.Transfer
+	}
+	if len(transfers) == 0 {
+		return &CreateTransferResponse{}, nil, nil
+	}
+	result, rsp, err := r.createTransferIdempotent(transfers, ctx, attributes)
+	var apiErr *APIError
+	if err != nil && !errors.As(err, &apiErr) {
+		return result, rsp, fmt.Errorf("TransfersResource.ResumeTransfers error: %w", err)
+	}
+	return result, rsp, err
+}
+
+//lookupTransfer method - find transaction by reference code, nil if it does not exist
+func (r *TransfersResource) lookupTransfer(ref string, ctx context.Context) (*GetDetailsResponseDetailParams, error) {
+	details := []GetDetailsDetailParamsInterface{&GetDetailsRequestDetailParamsStruct{Ref: ref}}
+	result, _, err := r.GetDetails(details, ctx, nil)
+	if errors.Is(err, ErrTransactionNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Details) == 0 {
+		return nil, nil
+	}
+	return result.Details[0], nil
+}
+
+//isRetryableError Check is failed operation retryable by the policy
+func isRetryableError(policy *RetryPolicy, resp *http.Response, err error) bool {
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) && resp != nil {
+		return policy.isRetryable(resp, nil)
+	}
+	return policy.isRetryable(resp, err)
+}
@@ -0,0 +1,385 @@
+package fasapay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type IdempotencyTestSuite struct {
+	suite.Suite
+}
+
+func (suite *IdempotencyTestSuite) TestAssignTransferRefs() {
+	transfer1 := &CreateTransferRequestParams{}
+	transfer2 := &CreateTransferRequestParams{Id: "123", Ref: "ref"}
+	err := assignTransferRefs([]*CreateTransferRequestParams{transfer1, transfer2})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), transfer1.Ref, 32)
+	assert.Equal(suite.T(), transfer1.Ref, transfer1.Id)
+	assert.Equal(suite.T(), "123", transfer2.Id)
+	assert.Equal(suite.T(), "ref", transfer2.Ref)
+}
+
+func (suite *IdempotencyTestSuite) TestAssignTransferRefsFromId() {
+	transfer1 := &CreateTransferRequestParams{Id: "payout-1"}
+	transfer2 := &CreateTransferRequestParams{Id: "payout-1"}
+	transfer3 := &CreateTransferRequestParams{Id: "payout-2"}
+	err := assignTransferRefs([]*CreateTransferRequestParams{transfer1, transfer2, transfer3})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), transfer1.Ref, 32)
+	assert.Equal(suite.T(), transfer1.Ref, transfer2.Ref)
+	assert.NotEqual(suite.T(), transfer1.Ref, transfer3.Ref)
+	assert.Equal(suite.T(), "payout-1", transfer1.Id)
+}
+
+func TestIdempotencyTestSuite(t *testing.T) {
+	suite.Run(t, new(IdempotencyTestSuite))
+}
+
+type IdempotentTransfersTestSuite struct {
+	suite.Suite
+	cfg       *Config
+	ctx       context.Context
+	store     *InMemoryTransferStore
+	testable  *TransfersResource
+	transfers int
+	details   int
+	sequence  []string
+}
+
+func (suite *IdempotentTransfersTestSuite) SetupTest() {
+	cfg := BuildStubConfig()
+	transport := BuildStubHttpTransport()
+	suite.cfg = cfg
+	suite.ctx = context.Background()
+	suite.store = NewInMemoryTransferStore()
+	suite.testable = &TransfersResource{ResourceAbstract: NewResourceAbstract(transport, cfg), store: suite.store}
+	suite.transfers = 0
+	suite.details = 0
+	suite.sequence = nil
+	httpmock.Activate()
+}
+
+func (suite *IdempotentTransfersTestSuite) TearDownTest() {
+	httpmock.DeactivateAndReset()
+}
+
+//registerResponders method - route transfer and details requests to different responders
+func (suite *IdempotentTransfersTestSuite) registerResponders(transfer httpmock.Responder, details httpmock.Responder) {
+	httpmock.RegisterResponder(http.MethodPost, suite.cfg.Uri, func(req *http.Request) (*http.Response, error) {
+		body, _ := ioutil.ReadAll(req.Body)
+		if strings.Contains(string(body), "<detail>") {
+			suite.details++
+			suite.sequence = append(suite.sequence, "detail")
+			return details(req)
+		}
+		suite.transfers++
+		suite.sequence = append(suite.sequence, "transfer")
+		return transfer(req)
+	})
+}
+
+func (suite *IdempotentTransfersTestSuite) buildTransfer() *CreateTransferRequestParams {
+	return &CreateTransferRequestParams{To: "FP89680", Amount: MustParseAmount("1000"), Currency: CurrencyCodeIDR, FeeMode: TransactionFeeModeFiS}
+}
+
+func (suite *IdempotentTransfersTestSuite) TestCreateTransferSavesRecord() {
+	body, _ := LoadStubResponseData("stubs/transfers/transfer/success.xml")
+	suite.registerResponders(httpmock.NewBytesResponder(http.StatusOK, body), httpmock.NewBytesResponder(http.StatusOK, body))
+
+	transfer := suite.buildTransfer()
+	result, resp, err := suite.testable.CreateTransfer([]*CreateTransferRequestParams{transfer}, suite.ctx, nil)
+	assert.NoError(suite.T(), err)
+	assert.NotEmpty(suite.T(), resp)
+	assert.Equal(suite.T(), "TR2011071917277", result.Transfers[0].BatchNumber)
+	assert.NotEmpty(suite.T(), transfer.Ref)
+	assert.Equal(suite.T(), transfer.Ref, transfer.Id)
+
+	record, ok, _ := suite.store.Load(transfer.Ref)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), TransferStateFinished, record.State)
+	assert.Equal(suite.T(), "TR2011071917277", record.Result.BatchNumber)
+	assert.Equal(suite.T(), 1, suite.transfers)
+	assert.Equal(suite.T(), 0, suite.details)
+}
+
+func (suite *IdempotentTransfersTestSuite) TestCreateTransferRepeatedCompleted() {
+	body, _ := LoadStubResponseData("stubs/transfers/transfer/success.xml")
+	suite.registerResponders(httpmock.NewBytesResponder(http.StatusOK, body), httpmock.NewBytesResponder(http.StatusOK, body))
+
+	transfers := []*CreateTransferRequestParams{suite.buildTransfer()}
+	_, _, _ = suite.testable.CreateTransfer(transfers, suite.ctx, nil)
+	result, resp, err := suite.testable.CreateTransfer(transfers, suite.ctx, nil)
+	assert.NoError(suite.T(), err)
+	assert.Nil(suite.T(), resp)
+	assert.Equal(suite.T(), "TR2011071917277", result.Transfers[0].BatchNumber)
+	assert.Equal(suite.T(), 1, suite.transfers)
+	assert.Equal(suite.T(), 0, suite.details)
+}
+
+func (suite *IdempotentTransfersTestSuite) TestCreateTransferPendingFound() {
+	transferBody, _ := LoadStubResponseData("stubs/transfers/transfer/success.xml")
+	detailsBody, _ := LoadStubResponseData("stubs/transfers/details/success.xml")
+	suite.registerResponders(httpmock.NewBytesResponder(http.StatusOK, transferBody), httpmock.NewBytesResponder(http.StatusOK, detailsBody))
+
+	transfer := suite.buildTransfer()
+	transfer.Ref = "ref-1"
+	_ = suite.store.SavePending(&TransferRecord{Ref: "ref-1", Transfer: suite.buildTransfer()})
+	_ = suite.store.MarkSubmitted("ref-1")
+
+	result, _, err := suite.testable.CreateTransfer([]*CreateTransferRequestParams{transfer}, suite.ctx, nil)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "TR2012092791234", result.Transfers[0].BatchNumber)
+	assert.Equal(suite.T(), 0, suite.transfers)
+	assert.Equal(suite.T(), 1, suite.details)
+
+	record, _, _ := suite.store.Load("ref-1")
+	assert.Equal(suite.T(), TransferStateFinished, record.State)
+	assert.Equal(suite.T(), "TR2012092791234", record.Result.BatchNumber)
+}
+
+func (suite *IdempotentTransfersTestSuite) TestCreateTransferPendingNotFound() {
+	transferBody, _ := LoadStubResponseData("stubs/transfers/transfer/success.xml")
+	detailsBody, _ := LoadStubResponseData("stubs/transfers/details/error.xml")
+	suite.registerResponders(httpmock.NewBytesResponder(http.StatusOK, transferBody), httpmock.NewBytesResponder(http.StatusOK, detailsBody))
+
+	transfer := suite.buildTransfer()
+	transfer.Ref = "ref-1"
+	_ = suite.store.SavePending(&TransferRecord{Ref: "ref-1", Transfer: suite.buildTransfer()})
+	_ = suite.store.MarkSubmitted("ref-1")
+
+	result, _, err := suite.testable.CreateTransfer([]*CreateTransferRequestParams{transfer}, suite.ctx, nil)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "TR2011071917277", result.Transfers[0].BatchNumber)
+	assert.Equal(suite.T(), 1, suite.transfers)
+	assert.Equal(suite.T(), 1, suite.details)
+}
+
+func (suite *IdempotentTransfersTestSuite) TestCreateTransferRetryAfterNetworkError() {
+	transferBody, _ := LoadStubResponseData("stubs/transfers/transfer/success.xml")
+	detailsBody, _ := LoadStubResponseData("stubs/transfers/details/error.xml")
+	calls := 0
+	transferResponder := func(req *http.Request) (*http.Response, error) {
+		calls++
+		if calls == 1 {
+			return nil, fmt.Errorf("connection reset")
+		}
+		return httpmock.NewBytesResponse(http.StatusOK, transferBody), nil
+	}
+	suite.registerResponders(transferResponder, httpmock.NewBytesResponder(http.StatusOK, detailsBody))
+	suite.testable.tr.retry = &RetryPolicy{MaxAttempts: 2}
+
+	result, _, err := suite.testable.CreateTransfer([]*CreateTransferRequestParams{suite.buildTransfer()}, suite.ctx, nil)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "TR2011071917277", result.Transfers[0].BatchNumber)
+	assert.Equal(suite.T(), 2, suite.transfers)
+	assert.Equal(suite.T(), 1, suite.details)
+}
+
+func (suite *IdempotentTransfersTestSuite) TestCreateTransferNetworkErrorWithoutRetry() {
+	suite.registerResponders(httpmock.NewErrorResponder(fmt.Errorf("connection reset")), httpmock.NewErrorResponder(fmt.Errorf("connection reset")))
+
+	transfer := suite.buildTransfer()
+	result, _, err := suite.testable.CreateTransfer([]*CreateTransferRequestParams{transfer}, suite.ctx, nil)
+	assert.Error(suite.T(), err)
+	assert.Nil(suite.T(), result)
+	assert.Equal(suite.T(), 1, suite.transfers)
+
+	record, ok, _ := suite.store.Load(transfer.Ref)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), TransferStateSubmitted, record.State)
+}
+
+func (suite *IdempotentTransfersTestSuite) TestCreateTransferApiError() {
+	body, _ := LoadStubResponseData("stubs/transfers/transfer/error.xml")
+	suite.registerResponders(httpmock.NewBytesResponder(http.StatusOK, body), httpmock.NewBytesResponder(http.StatusOK, body))
+
+	transfer := suite.buildTransfer()
+	result, _, err := suite.testable.CreateTransfer([]*CreateTransferRequestParams{transfer}, suite.ctx, nil)
+	assert.Error(suite.T(), err)
+	assert.NotEmpty(suite.T(), result)
+	var apiErr *APIError
+	assert.True(suite.T(), errors.As(err, &apiErr))
+
+	record, _, _ := suite.store.Load(transfer.Ref)
+	assert.Equal(suite.T(), TransferStateFailed, record.State)
+	assert.Equal(suite.T(), err.Error(), record.Error)
+	assert.Equal(suite.T(), TransferResultStatusRejected, result.Results[0].Status)
+}
+
+func (suite *IdempotentTransfersTestSuite) TestCreateTransferApiErrorNotProcessedLookedUp() {
+	errorBody, _ := LoadStubResponseData("stubs/transfers/transfer/error.xml")
+	notFound, _ := LoadStubResponseData("stubs/transfers/details/error.xml")
+	suite.registerResponders(httpmock.NewBytesResponder(http.StatusOK, errorBody), httpmock.NewBytesResponder(http.StatusOK, notFound))
+
+	unknown := &CreateTransferRequestParams{Id: "tid2", To: "FP89680", Amount: MustParseAmount("1000"), Currency: CurrencyCodeIDR}
+	rejected := &CreateTransferRequestParams{Id: "tid3", To: "FP89681", Amount: MustParseAmount("1000"), Currency: CurrencyCodeIDR}
+	transfers := []*CreateTransferRequestParams{unknown, rejected}
+	result, _, err := suite.testable.CreateTransfer(transfers, suite.ctx, nil)
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), TransferResultStatusNotProcessed, result.Results[0].Status)
+	assert.Equal(suite.T(), TransferResultStatusRejected, result.Results[1].Status)
+	record, _, _ := suite.store.Load(unknown.Ref)
+	assert.Equal(suite.T(), TransferStateSubmitted, record.State)
+	record, _, _ = suite.store.Load(rejected.Ref)
+	assert.Equal(suite.T(), TransferStateFailed, record.State)
+
+	httpmock.Reset()
+	successBody, _ := LoadStubResponseData("stubs/transfers/transfer/success.xml")
+	suite.registerResponders(httpmock.NewBytesResponder(http.StatusOK, successBody), httpmock.NewBytesResponder(http.StatusOK, notFound))
+	suite.sequence = nil
+	result, _, err = suite.testable.CreateTransfer(transfers, suite.ctx, nil)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []string{"detail", "transfer"}, suite.sequence)
+	assert.Equal(suite.T(), TransferResultStatusAccepted, result.Results[0].Status)
+	record, _, _ = suite.store.Load(unknown.Ref)
+	assert.Equal(suite.T(), TransferStateFinished, record.State)
+}
+
+func (suite *IdempotentTransfersTestSuite) TestCreateTransferRetryRebuiltParams() {
+	detailsBody, _ := LoadStubResponseData("stubs/transfers/details/success.xml")
+	suite.registerResponders(httpmock.NewErrorResponder(fmt.Errorf("timeout")), httpmock.NewBytesResponder(http.StatusOK, detailsBody))
+
+	first := &CreateTransferRequestParams{Id: "payout-1", To: "FP89680", Amount: MustParseAmount("1000"), Currency: CurrencyCodeIDR}
+	_, _, err := suite.testable.CreateTransfer([]*CreateTransferRequestParams{first}, suite.ctx, nil)
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), 1, suite.transfers)
+
+	rebuilt := &CreateTransferRequestParams{Id: "payout-1", To: "FP89680", Amount: MustParseAmount("1000"), Currency: CurrencyCodeIDR}
+	result, _, err := suite.testable.CreateTransfer([]*CreateTransferRequestParams{rebuilt}, suite.ctx, nil)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), first.Ref, rebuilt.Ref)
+	assert.Equal(suite.T(), "TR2012092791234", result.Transfers[0].BatchNumber)
+	assert.Equal(suite.T(), 1, suite.transfers)
+	assert.Equal(suite.T(), 1, suite.details)
+	record, _, _ := suite.store.Load(rebuilt.Ref)
+	assert.Equal(suite.T(), TransferStateFinished, record.State)
+}
+
+func (suite *IdempotentTransfersTestSuite) TestCreateTransferResults() {
+	body, _ := LoadStubResponseData("stubs/transfers/transfer/success.xml")
+	suite.registerResponders(httpmock.NewBytesResponder(http.StatusOK, body), httpmock.NewBytesResponder(http.StatusOK, body))
+
+	finished := suite.buildTransfer()
+	_, _, _ = suite.testable.CreateTransfer([]*CreateTransferRequestParams{finished}, suite.ctx, nil)
+	transfers := []*CreateTransferRequestParams{finished, suite.buildTransfer(), suite.buildTransfer()}
+	result, _, err := suite.testable.CreateTransfer(transfers, suite.ctx, nil)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), result.Results, 3)
+	assert.Equal(suite.T(), TransferResultStatusAccepted, result.Results[0].Status)
+	assert.Equal(suite.T(), finished.Id, result.Results[0].Id)
+	assert.Equal(suite.T(), TransferResultStatusAccepted, result.Results[1].Status)
+	assert.Equal(suite.T(), TransferResultStatusNotProcessed, result.Results[2].Status)
+	assert.Len(suite.T(), result.Transfers, 2)
+	for _, item := range result.Transfers {
+		assert.NotNil(suite.T(), item)
+	}
+
+	record, _, _ := suite.store.Load(transfers[2].Ref)
+	assert.Equal(suite.T(), TransferStateSubmitted, record.State)
+}
+
+func (suite *IdempotentTransfersTestSuite) TestCreateTransferShortResponse() {
+	body, _ := LoadStubResponseData("stubs/transfers/transfer/success.xml")
+	suite.registerResponders(httpmock.NewBytesResponder(http.StatusOK, body), httpmock.NewBytesResponder(http.StatusOK, body))
+
+	unknown := &CreateTransferRequestParams{To: "FP00001", Amount: MustParseAmount("5"), Currency: CurrencyCodeIDR}
+	paid := suite.buildTransfer()
+	result, _, err := suite.testable.CreateTransfer([]*CreateTransferRequestParams{unknown, paid}, suite.ctx, nil)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), TransferResultStatusNotProcessed, result.Results[0].Status)
+	assert.Equal(suite.T(), TransferResultStatusAccepted, result.Results[1].Status)
+	assert.Len(suite.T(), result.Transfers, 1)
+	assert.Equal(suite.T(), AccountNumber("FP89680"), result.Transfers[0].To)
+
+	record, _, _ := suite.store.Load(unknown.Ref)
+	assert.Equal(suite.T(), TransferStateSubmitted, record.State)
+	assert.Nil(suite.T(), record.Result)
+	record, _, _ = suite.store.Load(paid.Ref)
+	assert.Equal(suite.T(), TransferStateFinished, record.State)
+	assert.Equal(suite.T(), "TR2011071917277", record.Result.BatchNumber)
+}
+
+func (suite *IdempotentTransfersTestSuite) TestCreateTransferPendingNotSubmitted() {
+	body, _ := LoadStubResponseData("stubs/transfers/transfer/success.xml")
+	suite.registerResponders(httpmock.NewBytesResponder(http.StatusOK, body), httpmock.NewBytesResponder(http.StatusOK, body))
+
+	transfer := suite.buildTransfer()
+	transfer.Ref = "ref-1"
+	_ = suite.store.SavePending(&TransferRecord{Ref: "ref-1", Transfer: suite.buildTransfer()})
+
+	result, _, err := suite.testable.CreateTransfer([]*CreateTransferRequestParams{transfer}, suite.ctx, nil)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "TR2011071917277", result.Transfers[0].BatchNumber)
+	assert.Equal(suite.T(), 1, suite.transfers)
+	assert.Equal(suite.T(), 0, suite.details)
+}
+
+func (suite *IdempotentTransfersTestSuite) TestResumeTransfers() {
+	transferBody, _ := LoadStubResponseData("stubs/transfers/transfer/success.xml")
+	detailsBody, _ := LoadStubResponseData("stubs/transfers/details/success.xml")
+	suite.registerResponders(httpmock.NewBytesResponder(http.StatusOK, transferBody), httpmock.NewBytesResponder(http.StatusOK, detailsBody))
+
+	transfer1 := suite.buildTransfer()
+	transfer1.Ref = "ref-1"
+	transfer1.Id = "ref-1"
+	transfer2 := suite.buildTransfer()
+	transfer2.Ref = "ref-2"
+	transfer2.Id = "ref-2"
+	_ = suite.store.SavePending(&TransferRecord{Ref: "ref-1", Transfer: transfer1})
+	_ = suite.store.MarkSubmitted("ref-1")
+	_ = suite.store.SavePending(&TransferRecord{Ref: "ref-2", Transfer: transfer2})
+
+	result, _, err := suite.testable.ResumeTransfers(0, suite.ctx, nil)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), result.Transfers, 2)
+	assert.Equal(suite.T(), 1, suite.transfers)
+	assert.Equal(suite.T(), 1, suite.details)
+
+	stuck, _ := suite.store.ListStuck(0)
+	assert.Empty(suite.T(), stuck)
+	record1, _, _ := suite.store.Load("ref-1")
+	assert.Equal(suite.T(), "TR2012092791234", record1.Result.BatchNumber)
+	record2, _, _ := suite.store.Load("ref-2")
+	assert.Equal(suite.T(), "TR2011071917277", record2.Result.BatchNumber)
+}
+
+func (suite *IdempotentTransfersTestSuite) TestResumeTransfersNothingStuck() {
+	result, resp, err := suite.testable.ResumeTransfers(0, suite.ctx, nil)
+	assert.NoError(suite.T(), err)
+	assert.Nil(suite.T(), resp)
+	assert.Empty(suite.T(), result.Transfers)
+	assert.Equal(suite.T(), 0, httpmock.GetTotalCallCount())
+}
+
+func (suite *IdempotentTransfersTestSuite) TestResumeTransfersWithoutStore() {
+	suite.testable.store = nil
+	_, _, err := suite.testable.ResumeTransfers(0, suite.ctx, nil)
+	assert.Equal(suite.T(), "TransfersResource.ResumeTransfers error: transfer store is not set", err.Error())
+}
+
+func (suite *IdempotentTransfersTestSuite) TestCreateTransferRefReused() {
+	_ = suite.store.SavePending(&TransferRecord{Ref: "ref-1", Transfer: suite.buildTransfer()})
+	_ = suite.store.MarkSubmitted("ref-1")
+
+	transfer := suite.buildTransfer()
+	transfer.Ref = "ref-1"
+	transfer.Amount = MustParseAmount("2000")
+	result, resp, err := suite.testable.CreateTransfer([]*CreateTransferRequestParams{transfer}, suite.ctx, nil)
+	assert.Nil(suite.T(), result)
+	assert.Nil(suite.T(), resp)
+	assert.Equal(suite.T(), `TransfersResource.CreateTransfer error: transfer ref "ref-1" is already used by another transfer`, err.Error())
+	assert.Equal(suite.T(), 0, httpmock.GetTotalCallCount())
+}
+
+func TestIdempotentTransfersTestSuite(t *testing.T) {
+	suite.Run(t, new(IdempotentTransfersTestSuite))
+}
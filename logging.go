@@ -0,0 +1,71 @@
+package fasapay
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"time"
+)
+
+//redactedValue replacement of masked credentials
+const redactedValue = "***"
+
+//credentialsPattern pattern of credentials xml elements
+var credentialsPattern = regexp.MustCompile(`(<(api_key|token)>)[^<]*(</(api_key|token)>)`)
+
+//Logger interface - key/value structured logger (compatible with *slog.Logger)
+type Logger interface {
+	Info(msg string, args ...interface{})
+	Error(msg string, args ...interface{})
+}
+
+//LoggingMiddleware Create middleware which logs every API operation with masked credentials
+func LoggingMiddleware(logger Logger) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(ctx context.Context, rt *RoundTrip) error {
+			start := time.Now()
+			err := next(ctx, rt)
+			args := []interface{}{
+				"operation", string(rt.Operation),
+				"request_id", rt.RequestId,
+				"latency", time.Since(start),
+				"request", string(redactCredentials(rt.Request)),
+			}
+			if rt.Response != nil {
+				args = append(args, "status_code", rt.Response.StatusCode, "response", string(readResponseBody(rt.Response)))
+			}
+			if err != nil {
+				logger.Error("fasapay request failed", append(args, "error", err.Error())...)
+				return err
+			}
+			if rt.Result != nil && !rt.Result.IsSuccess() {
+				apiErr := rt.Result.GetAPIError()
+				logger.Error("fasapay request failed", append(args, "error_code", apiErr.Code, "error", apiErr.Error())...)
+				return err
+			}
+			logger.Info("fasapay request", args...)
+			return err
+		}
+	}
+}
+
+//redactCredentials mask api_key and token values in xml document
+func redactCredentials(document []byte) []byte {
+	return credentialsPattern.ReplaceAll(document, []byte("${1}"+redactedValue+"${3}"))
+}
+
+//readResponseBody read response body and reset it to the original unread state
+func readResponseBody(resp *http.Response) []byte {
+	if resp.Body == nil {
+		return nil
+	}
+	bodyBytes, err := ioutil.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return nil
+	}
+	return bodyBytes
+}
@@ -0,0 +1,128 @@
+package fasapay
+
+import (
+	"context"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+type stubLogEntry struct {
+	level string
+	msg   string
+	args  map[string]interface{}
+}
+
+type stubLogger struct {
+	entries []*stubLogEntry
+}
+
+func (l *stubLogger) log(level string, msg string, args []interface{}) {
+	entry := &stubLogEntry{level: level, msg: msg, args: map[string]interface{}{}}
+	for i := 0; i+1 < len(args); i += 2 {
+		entry.args[args[i].(string)] = args[i+1]
+	}
+	l.entries = append(l.entries, entry)
+}
+
+func (l *stubLogger) Info(msg string, args ...interface{}) {
+	l.log("info", msg, args)
+}
+
+func (l *stubLogger) Error(msg string, args ...interface{}) {
+	l.log("error", msg, args)
+}
+
+type LoggingTestSuite struct {
+	suite.Suite
+	cfg      *Config
+	ctx      context.Context
+	logger   *stubLogger
+	testable *AccountsResource
+}
+
+func (suite *LoggingTestSuite) SetupTest() {
+	cfg := BuildStubConfig()
+	transport := BuildStubHttpTransport()
+	suite.logger = &stubLogger{}
+	transport.logger = suite.logger
+	suite.cfg = cfg
+	suite.ctx = context.Background()
+	suite.testable = &AccountsResource{NewResourceAbstract(transport, cfg)}
+	httpmock.Activate()
+}
+
+func (suite *LoggingTestSuite) TearDownTest() {
+	httpmock.DeactivateAndReset()
+}
+
+func (suite *LoggingTestSuite) TestRedactCredentials() {
+	document := `<fasa_request id="1"><auth><api_key>11123548cd3a5e5613325132112becf</api_key><token>e910361e42dafdfd</token></auth><balance>IDR</balance></fasa_request>`
+	expected := `<fasa_request id="1"><auth><api_key>***</api_key><token>***</token></auth><balance>IDR</balance></fasa_request>`
+	assert.Equal(suite.T(), expected, string(redactCredentials([]byte(document))))
+}
+
+func (suite *LoggingTestSuite) TestReadResponseBody() {
+	rsp := BuildStubResponseFromString(http.StatusOK, "foo")
+	assert.Equal(suite.T(), "foo", string(readResponseBody(rsp)))
+	body, _ := ioutil.ReadAll(rsp.Body)
+	assert.Equal(suite.T(), "foo", string(body))
+	assert.Nil(suite.T(), readResponseBody(&http.Response{}))
+}
+
+func (suite *LoggingTestSuite) TestLogSuccess() {
+	body, _ := LoadStubResponseData("stubs/accounts/balances/success.xml")
+	httpmock.RegisterResponder(http.MethodPost, suite.cfg.Uri, httpmock.NewBytesResponder(http.StatusOK, body))
+
+	attributes := &RequestParamsAttributes{Id: "1234567", DateTime: BuildStubDateTime()}
+	_, resp, err := suite.testable.GetBalances([]CurrencyCode{CurrencyCodeIDR}, suite.ctx, attributes)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), suite.logger.entries, 1)
+	entry := suite.logger.entries[0]
+	assert.Equal(suite.T(), "info", entry.level)
+	assert.Equal(suite.T(), "GetBalances", entry.args["operation"])
+	assert.Equal(suite.T(), "1234567", entry.args["request_id"])
+	assert.Equal(suite.T(), http.StatusOK, entry.args["status_code"])
+	assert.Contains(suite.T(), entry.args, "latency")
+	assert.Equal(suite.T(), `<fasa_request id="1234567"><auth><api_key>***</api_key><token>***</token></auth><balance>IDR</balance></fasa_request>`, entry.args["request"])
+	assert.Equal(suite.T(), string(body), entry.args["response"])
+	assert.NotContains(suite.T(), entry.args["request"], TestableApiKey)
+	//response body is still readable
+	bodyRsp, _ := ioutil.ReadAll(resp.Body)
+	assert.Equal(suite.T(), body, bodyRsp)
+}
+
+func (suite *LoggingTestSuite) TestLogAPIError() {
+	body, _ := LoadStubResponseData("stubs/accounts/balances/error.xml")
+	httpmock.RegisterResponder(http.MethodPost, suite.cfg.Uri, httpmock.NewBytesResponder(http.StatusOK, body))
+
+	_, _, err := suite.testable.GetBalances([]CurrencyCode{CurrencyCodeIDR}, suite.ctx, nil)
+	assert.Error(suite.T(), err)
+	assert.Len(suite.T(), suite.logger.entries, 1)
+	entry := suite.logger.entries[0]
+	assert.Equal(suite.T(), "error", entry.level)
+	assert.Equal(suite.T(), uint64(40901), entry.args["error_code"])
+	assert.Equal(suite.T(), ErrorMessageWrongOrInactiveCurrency, entry.args["error"])
+}
+
+func (suite *LoggingTestSuite) TestLogHttpError() {
+	body, _ := LoadStubResponseData("stubs/errors/500.html")
+	httpmock.RegisterResponder(http.MethodPost, suite.cfg.Uri, httpmock.NewBytesResponder(http.StatusInternalServerError, body))
+
+	_, _, err := suite.testable.GetBalances([]CurrencyCode{CurrencyCodeIDR}, suite.ctx, nil)
+	assert.Error(suite.T(), err)
+	assert.Len(suite.T(), suite.logger.entries, 1)
+	entry := suite.logger.entries[0]
+	assert.Equal(suite.T(), "error", entry.level)
+	assert.Equal(suite.T(), http.StatusInternalServerError, entry.args["status_code"])
+	assert.Equal(suite.T(), "Bad request", entry.args["response"])
+	assert.NotContains(suite.T(), entry.args, "error_code")
+	assert.Contains(suite.T(), entry.args["error"], "unexpected HTTP response")
+}
+
+func TestLoggingTestSuite(t *testing.T) {
+	suite.Run(t, new(LoggingTestSuite))
+}
@@ -0,0 +1,137 @@
+package fasapay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+//MetricRequestsTotal API operations counter name
+const MetricRequestsTotal = "fasapay_requests_total"
+
+//MetricRequestDurationSeconds API operations latency histogram name
+const MetricRequestDurationSeconds = "fasapay_request_duration_seconds"
+
+//MetricLabelOperation operation label name
+const MetricLabelOperation = "operation"
+
+//MetricLabelEndpoint endpoint label name
+const MetricLabelEndpoint = "endpoint"
+
+//MetricLabelOutcome outcome label name
+const MetricLabelOutcome = "outcome"
+
+//MetricLabelErrorCode FasaPay error code label name
+const MetricLabelErrorCode = "error_code"
+
+//OutcomeSuccess operation succeeded
+const OutcomeSuccess = "success"
+
+//OutcomeAPIError FasaPay returned fasa_response with errors
+const OutcomeAPIError = "api_error"
+
+//OutcomeHTTPError unexpected HTTP response
+const OutcomeHTTPError = "http_error"
+
+//OutcomeNetworkError request was not completed (connection error, timeout, canceled context, etc.)
+const OutcomeNetworkError = "network_error"
+
+//MetricLabels type
+type MetricLabels map[string]string
+
+//MetricsSink interface - counters and histograms receiver
+type MetricsSink interface {
+	IncCounter(name string, labels MetricLabels)
+	ObserveHistogram(name string, value float64, labels MetricLabels)
+}
+
+//MetricsMiddleware Create middleware which reports latency and outcome of every API operation
+func MetricsMiddleware(sink MetricsSink) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(ctx context.Context, rt *RoundTrip) error {
+			start := time.Now()
+			err := next(ctx, rt)
+			labels := MetricLabels{
+				MetricLabelOperation: string(rt.Operation),
+				MetricLabelEndpoint:  rt.Endpoint,
+				MetricLabelOutcome:   OutcomeSuccess,
+				MetricLabelErrorCode: "",
+			}
+			var httpErr *HTTPError
+			if errors.As(err, &httpErr) {
+				labels[MetricLabelOutcome] = OutcomeHTTPError
+			} else if err != nil {
+				labels[MetricLabelOutcome] = OutcomeNetworkError
+			} else if rt.Result != nil && !rt.Result.IsSuccess() {
+				labels[MetricLabelOutcome] = OutcomeAPIError
+				labels[MetricLabelErrorCode] = fmt.Sprint(rt.Result.GetAPIError().Code)
+			}
+			sink.IncCounter(MetricRequestsTotal, labels)
+			sink.ObserveHistogram(MetricRequestDurationSeconds, time.Since(start).Seconds(), labels)
+			return err
+		}
+	}
+}
+
+//InMemoryMetrics struct - in-memory metrics sink (useful in tests)
+type InMemoryMetrics struct {
+	mu         sync.Mutex
+	counters   map[string]float64
+	histograms map[string][]float64
+}
+
+//NewInMemoryMetrics Create new in-memory metrics sink
+func NewInMemoryMetrics() *InMemoryMetrics {
+	return &InMemoryMetrics{counters: map[string]float64{}, histograms: map[string][]float64{}}
+}
+
+//IncCounter method implementation
+func (m *InMemoryMetrics) IncCounter(name string, labels MetricLabels) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.counters[buildMetricKey(name, labels)]++
+}
+
+//ObserveHistogram method implementation
+func (m *InMemoryMetrics) ObserveHistogram(name string, value float64, labels MetricLabels) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := buildMetricKey(name, labels)
+	m.histograms[key] = append(m.histograms[key], value)
+}
+
+//Counter method - counter value by name and labels
+func (m *InMemoryMetrics) Counter(name string, labels MetricLabels) float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.counters[buildMetricKey(name, labels)]
+}
+
+//Observations method - histogram observed values by name and labels
+func (m *InMemoryMetrics) Observations(name string, labels MetricLabels) []float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	values := m.histograms[buildMetricKey(name, labels)]
+	result := make([]float64, len(values))
+	copy(result, values)
+	return result
+}
+
+//buildMetricKey build unique metric key from name and sorted labels
+func buildMetricKey(name string, labels MetricLabels) string {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var sb strings.Builder
+	sb.WriteString(name)
+	for _, key := range keys {
+		sb.WriteString("," + key + "=" + labels[key])
+	}
+	return sb.String()
+}
@@ -0,0 +1,116 @@
+package fasapay
+
+import (
+	"context"
+	"errors"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"testing"
+)
+
+type InMemoryMetricsTestSuite struct {
+	suite.Suite
+	testable *InMemoryMetrics
+}
+
+func (suite *InMemoryMetricsTestSuite) SetupTest() {
+	suite.testable = NewInMemoryMetrics()
+}
+
+func (suite *InMemoryMetricsTestSuite) TestCounter() {
+	labels := MetricLabels{"operation": "GetBalances", "outcome": "success"}
+	suite.testable.IncCounter("foo", labels)
+	suite.testable.IncCounter("foo", MetricLabels{"outcome": "success", "operation": "GetBalances"})
+	suite.testable.IncCounter("foo", MetricLabels{"operation": "GetAccounts", "outcome": "success"})
+	assert.Equal(suite.T(), float64(2), suite.testable.Counter("foo", labels))
+	assert.Equal(suite.T(), float64(1), suite.testable.Counter("foo", MetricLabels{"operation": "GetAccounts", "outcome": "success"}))
+	assert.Equal(suite.T(), float64(0), suite.testable.Counter("bar", labels))
+}
+
+func (suite *InMemoryMetricsTestSuite) TestObservations() {
+	labels := MetricLabels{"operation": "GetBalances"}
+	suite.testable.ObserveHistogram("foo", 0.1, labels)
+	suite.testable.ObserveHistogram("foo", 0.2, labels)
+	assert.Equal(suite.T(), []float64{0.1, 0.2}, suite.testable.Observations("foo", labels))
+	assert.Empty(suite.T(), suite.testable.Observations("bar", labels))
+}
+
+func (suite *InMemoryMetricsTestSuite) TestBuildMetricKey() {
+	assert.Equal(suite.T(), "foo,a=1,b=2", buildMetricKey("foo", MetricLabels{"b": "2", "a": "1"}))
+	assert.Equal(suite.T(), "foo", buildMetricKey("foo", nil))
+}
+
+func TestInMemoryMetricsTestSuite(t *testing.T) {
+	suite.Run(t, new(InMemoryMetricsTestSuite))
+}
+
+type MetricsMiddlewareTestSuite struct {
+	suite.Suite
+	cfg      *Config
+	ctx      context.Context
+	metrics  *InMemoryMetrics
+	testable *TransfersResource
+}
+
+func (suite *MetricsMiddlewareTestSuite) SetupTest() {
+	cfg := BuildStubConfig()
+	transport := BuildStubHttpTransport()
+	suite.metrics = NewInMemoryMetrics()
+	transport.metrics = suite.metrics
+	suite.cfg = cfg
+	suite.ctx = context.Background()
+	suite.testable = &TransfersResource{ResourceAbstract: NewResourceAbstract(transport, cfg)}
+	httpmock.Activate()
+}
+
+func (suite *MetricsMiddlewareTestSuite) TearDownTest() {
+	httpmock.DeactivateAndReset()
+}
+
+func (suite *MetricsMiddlewareTestSuite) TestSuccess() {
+	body, _ := LoadStubResponseData("stubs/transfers/history/success.xml")
+	httpmock.RegisterResponder(http.MethodPost, suite.cfg.Uri, httpmock.NewBytesResponder(http.StatusOK, body))
+
+	_, _, err := suite.testable.GetHistory(&GetHistoryRequestParams{}, suite.ctx, nil)
+	assert.NoError(suite.T(), err)
+	_, _, err = suite.testable.GetHistory(&GetHistoryRequestParams{}, suite.ctx, nil)
+	assert.NoError(suite.T(), err)
+	labels := MetricLabels{"operation": "GetHistory", "endpoint": SandboxAPIUrl, "outcome": "success", "error_code": ""}
+	assert.Equal(suite.T(), float64(2), suite.metrics.Counter(MetricRequestsTotal, labels))
+	assert.Len(suite.T(), suite.metrics.Observations(MetricRequestDurationSeconds, labels), 2)
+}
+
+func (suite *MetricsMiddlewareTestSuite) TestAPIError() {
+	body, _ := LoadStubResponseData("stubs/transfers/transfer/error.xml")
+	httpmock.RegisterResponder(http.MethodPost, suite.cfg.Uri, httpmock.NewBytesResponder(http.StatusOK, body))
+
+	transfer := &CreateTransferRequestParams{To: "FP89680", Amount: MustParseAmount("1000"), Currency: CurrencyCodeIDR}
+	_, _, err := suite.testable.CreateTransfer([]*CreateTransferRequestParams{transfer}, suite.ctx, nil)
+	assert.Error(suite.T(), err)
+	labels := MetricLabels{"operation": "CreateTransfer", "endpoint": SandboxAPIUrl, "outcome": "api_error", "error_code": "40600"}
+	assert.Equal(suite.T(), float64(1), suite.metrics.Counter(MetricRequestsTotal, labels))
+}
+
+func (suite *MetricsMiddlewareTestSuite) TestHttpError() {
+	httpmock.RegisterResponder(http.MethodPost, suite.cfg.Uri, httpmock.NewStringResponder(http.StatusBadGateway, "Bad gateway"))
+
+	_, _, err := suite.testable.GetHistory(&GetHistoryRequestParams{}, suite.ctx, nil)
+	assert.Error(suite.T(), err)
+	labels := MetricLabels{"operation": "GetHistory", "endpoint": SandboxAPIUrl, "outcome": "http_error", "error_code": ""}
+	assert.Equal(suite.T(), float64(1), suite.metrics.Counter(MetricRequestsTotal, labels))
+}
+
+func (suite *MetricsMiddlewareTestSuite) TestNetworkError() {
+	httpmock.RegisterResponder(http.MethodPost, suite.cfg.Uri, httpmock.NewErrorResponder(errors.New("connection reset by peer")))
+
+	_, _, err := suite.testable.GetHistory(&GetHistoryRequestParams{}, suite.ctx, nil)
+	assert.Error(suite.T(), err)
+	labels := MetricLabels{"operation": "GetHistory", "endpoint": SandboxAPIUrl, "outcome": "network_error", "error_code": ""}
+	assert.Equal(suite.T(), float64(1), suite.metrics.Counter(MetricRequestsTotal, labels))
+}
+
+func TestMetricsMiddlewareTestSuite(t *testing.T) {
+	suite.Run(t, new(MetricsMiddlewareTestSuite))
+}
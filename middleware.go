@@ -0,0 +1,61 @@
+package fasapay
+
+import (
+	"context"
+	"net/http"
+)
+
+//Operation type
+type Operation string
+
+//OperationGetBalances const
+const OperationGetBalances Operation = "GetBalances"
+
+//OperationGetAccounts const
+const OperationGetAccounts Operation = "GetAccounts"
+
+//OperationCreateTransfer const
+const OperationCreateTransfer Operation = "CreateTransfer"
+
+//OperationGetHistory const
+const OperationGetHistory Operation = "GetHistory"
+
+//OperationGetDetails const
+const OperationGetDetails Operation = "GetDetails"
+
+//IsIdempotent method - check is operation safe to repeat
+func (o Operation) IsIdempotent() bool {
+	return o != OperationCreateTransfer
+}
+
+//ResponseBodyInterface interface - parsed fasa_response
+type ResponseBodyInterface interface {
+	IsSuccess() bool
+	GetError() string
+	GetAPIError() *APIError
+}
+
+//RoundTrip struct - single API operation passed through the middlewares chain
+type RoundTrip struct {
+	Operation Operation             //API operation name
+	RequestId string                //fasa_request id attribute
+	Items     int                   //number of items in the request batch
+	Request   []byte                //outgoing fasa_request xml document
+	Endpoint  string                //endpoint which served the request (available after next handler call)
+	Response  *http.Response        //raw http response (available after next handler call)
+	Result    ResponseBodyInterface //parsed fasa_response (available after next handler call)
+}
+
+//RoundTripFunc func - API operation handler
+type RoundTripFunc func(ctx context.Context, rt *RoundTrip) error
+
+//Middleware func - API operation interceptor
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+//chainMiddlewares Wrap handler by middlewares, the first middleware is the outermost one
+func chainMiddlewares(handler RoundTripFunc, middlewares []Middleware) RoundTripFunc {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
@@ -0,0 +1,107 @@
+package fasapay
+
+import (
+	"context"
+	"errors"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"testing"
+)
+
+type MiddlewareTestSuite struct {
+	suite.Suite
+	cfg      *Config
+	ctx      context.Context
+	testable *AccountsResource
+}
+
+func (suite *MiddlewareTestSuite) SetupTest() {
+	cfg := BuildStubConfig()
+	suite.cfg = cfg
+	suite.ctx = context.Background()
+	suite.testable = &AccountsResource{NewResourceAbstract(BuildStubHttpTransport(), cfg)}
+	httpmock.Activate()
+}
+
+func (suite *MiddlewareTestSuite) TearDownTest() {
+	httpmock.DeactivateAndReset()
+}
+
+func (suite *MiddlewareTestSuite) TestOperationIsIdempotent() {
+	assert.True(suite.T(), OperationGetBalances.IsIdempotent())
+	assert.True(suite.T(), OperationGetAccounts.IsIdempotent())
+	assert.True(suite.T(), OperationGetHistory.IsIdempotent())
+	assert.True(suite.T(), OperationGetDetails.IsIdempotent())
+	assert.False(suite.T(), OperationCreateTransfer.IsIdempotent())
+}
+
+func (suite *MiddlewareTestSuite) TestChainMiddlewaresOrder() {
+	var calls []string
+	build := func(name string) Middleware {
+		return func(next RoundTripFunc) RoundTripFunc {
+			return func(ctx context.Context, rt *RoundTrip) error {
+				calls = append(calls, name+":before")
+				err := next(ctx, rt)
+				calls = append(calls, name+":after")
+				return err
+			}
+		}
+	}
+	handler := func(ctx context.Context, rt *RoundTrip) error {
+		calls = append(calls, "handler")
+		return nil
+	}
+	err := chainMiddlewares(handler, []Middleware{build("first"), build("second")})(suite.ctx, &RoundTrip{})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []string{"first:before", "second:before", "handler", "second:after", "first:after"}, calls)
+}
+
+func (suite *MiddlewareTestSuite) TestMiddlewareSeesRoundTrip() {
+	body, _ := LoadStubResponseData("stubs/accounts/balances/success.xml")
+	httpmock.RegisterResponder(http.MethodPost, suite.cfg.Uri, httpmock.NewBytesResponder(http.StatusOK, body))
+	var captured RoundTrip
+	suite.testable.tr.middlewares = []Middleware{func(next RoundTripFunc) RoundTripFunc {
+		return func(ctx context.Context, rt *RoundTrip) error {
+			err := next(ctx, rt)
+			captured = *rt
+			return err
+		}
+	}}
+
+	attributes := &RequestParamsAttributes{Id: "1234567", DateTime: BuildStubDateTime()}
+	result, _, err := suite.testable.GetBalances([]CurrencyCode{CurrencyCodeIDR}, suite.ctx, attributes)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), OperationGetBalances, captured.Operation)
+	assert.Equal(suite.T(), "1234567", captured.RequestId)
+	expected := `<fasa_request id="1234567"><auth><api_key>11123548cd3a5e5613325132112becf</api_key><token>e910361e42dafdfd100b19701c2ef403858cab640fd699afc67b78c7603ddb1b</token></auth><balance>IDR</balance></fasa_request>`
+	assert.Equal(suite.T(), expected, string(captured.Request))
+	assert.Equal(suite.T(), http.StatusOK, captured.Response.StatusCode)
+	assert.Equal(suite.T(), result, captured.Result)
+	assert.True(suite.T(), captured.Result.IsSuccess())
+}
+
+func (suite *MiddlewareTestSuite) TestMiddlewareFaultInjection() {
+	calls := 0
+	httpmock.RegisterResponder(http.MethodPost, suite.cfg.Uri, func(req *http.Request) (*http.Response, error) {
+		calls++
+		return nil, nil
+	})
+	suite.testable.tr.middlewares = []Middleware{func(next RoundTripFunc) RoundTripFunc {
+		return func(ctx context.Context, rt *RoundTrip) error {
+			return errors.New("injected fault")
+		}
+	}}
+
+	result, resp, err := suite.testable.GetBalances([]CurrencyCode{CurrencyCodeIDR}, suite.ctx, nil)
+	assert.Error(suite.T(), err)
+	assert.Nil(suite.T(), result)
+	assert.Nil(suite.T(), resp)
+	assert.Equal(suite.T(), "AccountsResource.GetBalances error: injected fault", err.Error())
+	assert.Equal(suite.T(), 0, calls)
+}
+
+func TestMiddlewareTestSuite(t *testing.T) {
+	suite.Run(t, new(MiddlewareTestSuite))
+}
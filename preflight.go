@@ -0,0 +1,182 @@
+package fasapay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+//activeAccountStatuses account statuses allowing to receive transfers (statuses of FasaPay account details response)
+var activeAccountStatuses = map[string]bool{
+	"STORE":    true,
+	"VERIFIED": true,
+}
+
+//IsActive method - is account allowed to receive transfers (any status other than Store or Verified is not active)
+func (p *GetAccountsResponseParams) IsActive() bool {
+	return activeAccountStatuses[strings.ToUpper(strings.TrimSpace(p.Status))]
+}
+
+//TransferCheck struct - pre-flight check result of the single transfer
+type TransferCheck struct {
+	Index     int                          `json:"index"`
+	Transfer  *CreateTransferRequestParams `json:"transfer"`
+	Quote     *TransferQuote               `json:"quote,omitempty"`     //fee and total debited from the sender
+	Recipient *GetAccountsResponseParams   `json:"recipient,omitempty"` //recipient account, nil if not found
+	Problems  []string                     `json:"problems,omitempty"`
+}
+
+//IsReady method
+func (tc *TransferCheck) IsReady() bool {
+	return len(tc.Problems) == 0
+}
+
+//BalanceCheck struct - pre-flight check result of the currency balance
+type BalanceCheck struct {
+	Currency    CurrencyCode `json:"currency"`
+	Required    Amount       `json:"required"`              //total debited by all transfers including fees
+	Available   Amount       `json:"available"`             //current balance
+	Unavailable bool         `json:"unavailable,omitempty"` //balance is not returned by FasaPay
+}
+
+//IsSufficient method - unavailable balance is never sufficient
+func (bc *BalanceCheck) IsSufficient() bool {
+	return !bc.Unavailable && bc.Available.Cmp(bc.Required) >= 0
+}
+
+//PrepareTransfersReport struct - go/no-go report of the transfers
+type PrepareTransfersReport struct {
+	Transfers []*TransferCheck `json:"transfers"`
+	Balances  []*BalanceCheck  `json:"balances"`
+}
+
+//IsReady method - can all transfers be submitted
+func (r *PrepareTransfersReport) IsReady() bool {
+	return len(r.Problems()) == 0
+}
+
+//Problems method - all found problems
+func (r *PrepareTransfersReport) Problems() []string {
+	var problems []string
+	for _, check := range r.Transfers {
+		for _, problem := range check.Problems {
+			problems = append(problems, fmt.Sprintf("transfer %d: %s", check.Index, problem))
+		}
+	}
+	for _, check := range r.Balances {
+		if check.Unavailable {
+			problems = append(problems, fmt.Sprintf("%s balance is not available: required %s", check.Currency, check.Required))
+		} else if !check.IsSufficient() {
+			problems = append(problems, fmt.Sprintf("insufficient %s balance: required %s, available %s", check.Currency, check.Required, check.Available))
+		}
+	}
+	return problems
+}
+
+//PrepareTransfers method - check transfers before submission without sending them:
+//validate params, verify recipients exist and are active, sum totals per currency including fees (DefaultFeeSchedule if schedule is nil)
+//and compare them with balances. The transfers are not modified, the report holds their normalized copies.
+func (c *Client) PrepareTransfers(transfers []*CreateTransferRequestParams, ctx context.Context, schedule *FeeSchedule) (*PrepareTransfersReport, error) {
+	transfers = normalizeTransfers(transfers)
+	report := &PrepareTransfersReport{Transfers: make([]*TransferCheck, len(transfers))}
+	for index, transfer := range transfers {
+		report.Transfers[index] = &TransferCheck{Index: index, Transfer: transfer}
+	}
+	var ve *ValidationError
+	if err := c.Transfers().validateTransferParams(transfers); errors.As(err, &ve) {
+		for _, fieldError := range ve.Errors {
+			check := report.Transfers[fieldError.Index]
+			check.Problems = append(check.Problems, fieldError.Message)
+		}
+	}
+
+	recipients, err := c.lookupRecipients(report.Transfers, ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Client.PrepareTransfers error: %w", err)
+	}
+	required := map[CurrencyCode]Amount{}
+	for _, check := range report.Transfers {
+		if !check.IsReady() {
+			continue
+		}
+		check.Recipient = recipients[check.Transfer.To]
+		if check.Recipient == nil {
+			check.Problems = append(check.Problems, fmt.Sprintf(`recipient account "%s" is not found`, check.Transfer.To))
+		} else if !check.Recipient.IsActive() {
+			check.Problems = append(check.Problems, fmt.Sprintf(`recipient account "%s" is not active (status "%s")`, check.Transfer.To, check.Recipient.Status))
+		}
+		quote, err := QuoteTransfer(check.Transfer, schedule)
+		if err != nil {
+			check.Problems = append(check.Problems, err.Error())
+			continue
+		}
+		check.Quote = quote
+		required[quote.Currency] = required[quote.Currency].Add(quote.Total)
+	}
+	if len(required) == 0 {
+		return report, nil
+	}
+
+	codes := make([]CurrencyCode, 0, len(required))
+	for code := range required {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool {
+		return codes[i] < codes[j]
+	})
+	balances, _, err := c.Accounts().GetBalances(codes, ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Client.PrepareTransfers error: %w", err)
+	}
+	for _, code := range codes {
+		available, ok := balances.Balances.Get(code)
+		report.Balances = append(report.Balances, &BalanceCheck{Currency: code, Required: required[code], Available: available, Unavailable: !ok})
+	}
+	return report, nil
+}
+
+//lookupRecipients method - find recipient accounts of valid transfers
+func (c *Client) lookupRecipients(checks []*TransferCheck, ctx context.Context) (map[AccountNumber]*GetAccountsResponseParams, error) {
+	var accounts []AccountNumber
+	seen := map[AccountNumber]bool{}
+	for _, check := range checks {
+		if check.IsReady() && !seen[check.Transfer.To] {
+			seen[check.Transfer.To] = true
+			accounts = append(accounts, check.Transfer.To)
+		}
+	}
+	recipients := map[AccountNumber]*GetAccountsResponseParams{}
+	if len(accounts) == 0 {
+		return recipients, nil
+	}
+	if err := c.lookupAccounts(accounts, recipients, ctx); err != nil {
+		return nil, err
+	}
+	return recipients, nil
+}
+
+//lookupAccounts method - find accounts and put them into recipients.
+//If some account is not found FasaPay rejects the whole request, so the failed request is split in halves
+//until unknown accounts are isolated (about 2*log2(n) extra requests per unknown account).
+func (c *Client) lookupAccounts(accounts []AccountNumber, recipients map[AccountNumber]*GetAccountsResponseParams, ctx context.Context) error {
+	result, _, err := c.Accounts().GetAccounts(accounts, ctx, nil)
+	if errors.Is(err, ErrAccountNotFound) {
+		if len(accounts) == 1 {
+			return nil
+		}
+		middle := len(accounts) / 2
+		if err := c.lookupAccounts(accounts[:middle], recipients, ctx); err != nil {
+			return err
+		}
+		return c.lookupAccounts(accounts[middle:], recipients, ctx)
+	}
+	if err != nil {
+		return err
+	}
+	for _, recipient := range result.Accounts {
+		recipients[recipient.Account] = recipient
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+package fasapay
+
+import (
+	"context"
+	"fmt"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+type PrepareTransfersTestSuite struct {
+	suite.Suite
+	ctx      context.Context
+	testable *Client
+	accounts map[string]string
+	balances []byte
+	requests []string
+}
+
+func (suite *PrepareTransfersTestSuite) SetupTest() {
+	suite.ctx = context.Background()
+	suite.testable, _ = NewClientFromConfig(BuildStubConfig(), nil)
+	suite.accounts = map[string]string{"FP00001": "Verified", "FP00002": "Suspended", "FP00003": "Store"}
+	suite.balances, _ = LoadStubResponseData("stubs/accounts/balances/success.xml")
+	suite.requests = nil
+	httpmock.Activate()
+	suite.registerResponder()
+}
+
+func (suite *PrepareTransfersTestSuite) TearDownTest() {
+	httpmock.DeactivateAndReset()
+}
+
+//registerResponder method - respond with known accounts (reject the whole request if any is unknown) and stub balances
+func (suite *PrepareTransfersTestSuite) registerResponder() {
+	notFound, _ := LoadStubResponseData("stubs/accounts/details/error.xml")
+	accountRegexp := regexp.MustCompile(`<account>(FP[0-9]+)</account>`)
+	httpmock.RegisterResponder(http.MethodPost, BuildStubConfig().Uri, func(req *http.Request) (*http.Response, error) {
+		body, _ := ioutil.ReadAll(req.Body)
+		if strings.Contains(string(body), "<balance>") {
+			suite.requests = append(suite.requests, "balances")
+			return httpmock.NewBytesResponse(http.StatusOK, suite.balances), nil
+		}
+		suite.requests = append(suite.requests, "accounts")
+		var accounts strings.Builder
+		for _, match := range accountRegexp.FindAllStringSubmatch(string(body), -1) {
+			status, ok := suite.accounts[match[1]]
+			if !ok {
+				return httpmock.NewBytesResponse(http.StatusOK, notFound), nil
+			}
+			fmt.Fprintf(&accounts, `<account><fullname>Name</fullname><account>%s</account><status>%s</status></account>`, match[1], status)
+		}
+		return httpmock.NewStringResponse(http.StatusOK, `<fasa_response id="1" date_time="2013-01-01T10:58:43+07:00">`+accounts.String()+`</fasa_response>`), nil
+	})
+}
+
+func (suite *PrepareTransfersTestSuite) TestIsActive() {
+	assert.True(suite.T(), (&GetAccountsResponseParams{Status: "Verified"}).IsActive())
+	assert.True(suite.T(), (&GetAccountsResponseParams{Status: "Store"}).IsActive())
+	assert.True(suite.T(), (&GetAccountsResponseParams{Status: " verified "}).IsActive())
+	assert.False(suite.T(), (&GetAccountsResponseParams{Status: "Unverified"}).IsActive())
+	assert.False(suite.T(), (&GetAccountsResponseParams{Status: ""}).IsActive())
+	assert.False(suite.T(), (&GetAccountsResponseParams{Status: "suspended"}).IsActive())
+	assert.False(suite.T(), (&GetAccountsResponseParams{Status: "Blocked"}).IsActive())
+}
+
+func (suite *PrepareTransfersTestSuite) TestPrepareTransfersReady() {
+	transfers := []*CreateTransferRequestParams{
+		{To: "FP00001", Amount: MustParseAmount("1000"), Currency: CurrencyCodeIDR, FeeMode: TransactionFeeModeFiS},
+		{To: "fp00003", Amount: MustParseAmount("100"), Currency: "usd", FeeMode: TransactionFeeModeFiS},
+		{To: "FP00001", Amount: MustParseAmount("2000"), Currency: CurrencyCodeIDR},
+	}
+	report, err := suite.testable.PrepareTransfers(transfers, suite.ctx, nil)
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), report.IsReady())
+	assert.Empty(suite.T(), report.Problems())
+	assert.Equal(suite.T(), []string{"accounts", "balances"}, suite.requests)
+
+	assert.Len(suite.T(), report.Transfers, 3)
+	assert.Equal(suite.T(), "Verified", report.Transfers[0].Recipient.Status)
+	assert.Equal(suite.T(), "100.00", report.Transfers[0].Quote.Fee.String())
+	assert.Equal(suite.T(), AccountNumber("FP00003"), report.Transfers[1].Recipient.Account)
+
+	assert.Len(suite.T(), report.Balances, 2)
+	assert.Equal(suite.T(), CurrencyCodeIDR, report.Balances[0].Currency)
+	assert.Equal(suite.T(), "3100.00", report.Balances[0].Required.String())
+	assert.Equal(suite.T(), "19092587.45", report.Balances[0].Available.String())
+	assert.True(suite.T(), report.Balances[0].IsSufficient())
+	assert.Equal(suite.T(), CurrencyCodeUSD, report.Balances[1].Currency)
+	assert.Equal(suite.T(), "100.50", report.Balances[1].Required.String())
+}
+
+func (suite *PrepareTransfersTestSuite) TestPrepareTransfersInsufficientBalance() {
+	transfers := []*CreateTransferRequestParams{
+		{To: "FP00001", Amount: MustParseAmount("3985"), Currency: CurrencyCodeUSD, FeeMode: TransactionFeeModeFiS},
+	}
+	report, err := suite.testable.PrepareTransfers(transfers, suite.ctx, nil)
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), report.IsReady())
+	assert.False(suite.T(), report.Balances[0].IsSufficient())
+	assert.Equal(suite.T(), []string{"insufficient USD balance: required 3990.00, available 3987.31"}, report.Problems())
+}
+
+func (suite *PrepareTransfersTestSuite) TestPrepareTransfersBalanceUnavailable() {
+	suite.balances = []byte(`<fasa_response id="1" date_time="2013-01-01T10:58:43+07:00"></fasa_response>`)
+	transfers := []*CreateTransferRequestParams{
+		{To: "FP00001", Amount: MustParseAmount("1000"), Currency: CurrencyCodeIDR, FeeMode: TransactionFeeModeFiS},
+	}
+	report, err := suite.testable.PrepareTransfers(transfers, suite.ctx, nil)
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), report.IsReady())
+	assert.True(suite.T(), report.Balances[0].Unavailable)
+	assert.False(suite.T(), report.Balances[0].IsSufficient())
+	assert.Equal(suite.T(), []string{"IDR balance is not available: required 1100.00"}, report.Problems())
+}
+
+func (suite *PrepareTransfersTestSuite) TestPrepareTransfersFeeNotLessThanAmount() {
+	transfers := []*CreateTransferRequestParams{
+		{To: "FP00001", Amount: MustParseAmount("50"), Currency: CurrencyCodeIDR},
+	}
+	report, err := suite.testable.PrepareTransfers(transfers, suite.ctx, nil)
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), report.IsReady())
+	assert.Nil(suite.T(), report.Transfers[0].Quote)
+	assert.Equal(suite.T(), []string{"transfer 0: net amount -50.00 IDR is not positive, fee 100.00 is not less than amount 50"}, report.Problems())
+}
+
+func (suite *PrepareTransfersTestSuite) TestPrepareTransfersKeepsParams() {
+	transfer := &CreateTransferRequestParams{To: " fp00001", Amount: MustParseAmount("1000"), Currency: "idr"}
+	report, err := suite.testable.PrepareTransfers([]*CreateTransferRequestParams{transfer}, suite.ctx, nil)
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), report.IsReady())
+	assert.Equal(suite.T(), AccountNumber(" fp00001"), transfer.To)
+	assert.Equal(suite.T(), CurrencyCode("idr"), transfer.Currency)
+	assert.Equal(suite.T(), AccountNumber("FP00001"), report.Transfers[0].Transfer.To)
+}
+
+func (suite *PrepareTransfersTestSuite) TestPrepareTransfersRecipientProblems() {
+	transfers := []*CreateTransferRequestParams{
+		{To: "FP00001", Amount: MustParseAmount("1000"), Currency: CurrencyCodeIDR},
+		{To: "FP00002", Amount: MustParseAmount("1000"), Currency: CurrencyCodeIDR},
+		{To: "FP00009", Amount: MustParseAmount("1000"), Currency: CurrencyCodeIDR},
+		{To: "FP00001", Currency: CurrencyCodeIDR},
+	}
+	report, err := suite.testable.PrepareTransfers(transfers, suite.ctx, nil)
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), report.IsReady())
+	assert.Equal(suite.T(), []string{"accounts", "accounts", "accounts", "accounts", "accounts", "balances"}, suite.requests)
+	assert.True(suite.T(), report.Transfers[0].IsReady())
+	assert.Equal(suite.T(), []string{
+		`transfer 1: recipient account "FP00002" is not active (status "Suspended")`,
+		`transfer 2: recipient account "FP00009" is not found`,
+		`transfer 3: parameter "amount" is empty`,
+	}, report.Problems())
+	assert.Nil(suite.T(), report.Transfers[3].Quote)
+	assert.Equal(suite.T(), "3000", report.Balances[0].Required.String())
+}
+
+func (suite *PrepareTransfersTestSuite) TestPrepareTransfersUnknownRecipientBisected() {
+	var transfers []*CreateTransferRequestParams
+	for i := 1; i <= 64; i++ {
+		account := fmt.Sprintf("FP%05d", 100+i)
+		if i != 42 {
+			suite.accounts[account] = "Verified"
+		}
+		transfers = append(transfers, &CreateTransferRequestParams{To: AccountNumber(account), Amount: MustParseAmount("1000"), Currency: CurrencyCodeIDR})
+	}
+	report, err := suite.testable.PrepareTransfers(transfers, suite.ctx, nil)
+	assert.NoError(suite.T(), err)
+	found := 0
+	for _, check := range report.Transfers {
+		if check.Recipient != nil {
+			found++
+		}
+	}
+	assert.Equal(suite.T(), 63, found)
+	assert.Equal(suite.T(), []string{`recipient account "FP00142" is not found`}, report.Transfers[41].Problems)
+	assert.Equal(suite.T(), 14, len(suite.requests))
+}
+
+func (suite *PrepareTransfersTestSuite) TestPrepareTransfersNothingValid() {
+	transfers := []*CreateTransferRequestParams{{Amount: MustParseAmount("1000"), Currency: CurrencyCodeIDR}}
+	report, err := suite.testable.PrepareTransfers(transfers, suite.ctx, nil)
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), report.IsReady())
+	assert.Empty(suite.T(), suite.requests)
+}
+
+func (suite *PrepareTransfersTestSuite) TestPrepareTransfersApiError() {
+	httpmock.Reset()
+	httpmock.RegisterResponder(http.MethodPost, BuildStubConfig().Uri, httpmock.NewErrorResponder(fmt.Errorf("connection reset")))
+	transfers := []*CreateTransferRequestParams{{To: "FP00001", Amount: MustParseAmount("1000"), Currency: CurrencyCodeIDR}}
+	report, err := suite.testable.PrepareTransfers(transfers, suite.ctx, nil)
+	assert.Nil(suite.T(), report)
+	assert.Contains(suite.T(), err.Error(), "Client.PrepareTransfers error: AccountsResource.GetAccounts error:")
+}
+
+func TestPrepareTransfersTestSuite(t *testing.T) {
+	suite.Run(t, new(PrepareTransfersTestSuite))
+}
@@ -2,6 +2,7 @@ package fasapay
 
 import (
 	"bytes"
+	"context"
 	"encoding/xml"
 	"fmt"
 	"io/ioutil"
@@ -24,11 +25,16 @@ func (ra *ResourceAbstract) buildAuthRequestParams(dt time.Time) *RequestAuthPar
 	return params
 }
 
+//newRequestParamsAttributes Create default request attributes (current time and its unix timestamp as request id)
+func newRequestParamsAttributes() *RequestParamsAttributes {
+	dt := time.Now().UTC()
+	return &RequestParamsAttributes{Id: fmt.Sprint(dt.Unix()), DateTime: dt}
+}
+
 //BuildParams method
 func (ra *ResourceAbstract) buildRequestParams(attributes *RequestParamsAttributes) RequestParams {
 	if attributes == nil {
-		dt := time.Now().UTC()
-		attributes = &RequestParamsAttributes{Id: fmt.Sprint(dt.Unix()), DateTime: dt}
+		attributes = newRequestParamsAttributes()
 	}
 	return RequestParams{Id: attributes.Id, Auth: ra.buildAuthRequestParams(attributes.DateTime)}
 }
@@ -39,9 +45,39 @@ func (ra *ResourceAbstract) marshalRequestParams(request interface{}) ([]byte, e
 	if err != nil {
 		return nil, err
 	}
+	return ra.buildRequestBody(bts), nil
+}
+
+//BuildRequestBody method
+func (ra *ResourceAbstract) buildRequestBody(document []byte) []byte {
 	req := []byte("req=")
-	req = append(req, bts...)
-	return req, nil
+	req = append(req, document...)
+	return req
+}
+
+//Execute method - marshal request, pass it through the middlewares chain and unmarshal response into result
+func (ra *ResourceAbstract) execute(ctx context.Context, operation Operation, request requestParamsInterface, result ResponseBodyInterface) (*http.Response, error) {
+	document, err := xml.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	rt := &RoundTrip{Operation: operation, RequestId: request.getRequestId(), Items: request.getItemsCount(), Request: document, Result: result}
+	err = chainMiddlewares(ra.roundTrip, ra.tr.buildMiddlewares())(ctx, rt)
+	return rt.Response, err
+}
+
+//RoundTrip method - send request and unmarshal response (the innermost handler of the middlewares chain)
+func (ra *ResourceAbstract) roundTrip(ctx context.Context, rt *RoundTrip) error {
+	var err error
+	if rt.Operation.IsIdempotent() {
+		rt.Response, rt.Endpoint, err = ra.tr.sendRequestWithRetry(ctx, ra.buildRequestBody(rt.Request))
+	} else {
+		rt.Response, rt.Endpoint, err = ra.tr.sendRequest(ctx, ra.buildRequestBody(rt.Request), false)
+	}
+	if err != nil {
+		return err
+	}
+	return ra.unmarshalResponse(rt.Response, rt.Result)
 }
 
 //UnmarshalResponse method
@@ -53,9 +89,26 @@ func (ra *ResourceAbstract) unmarshalResponse(resp *http.Response, v interface{}
 	}
 	//reset the response body to the original unread state
 	resp.Body = ioutil.NopCloser(bytes.NewBuffer(bodyBytes))
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices || !isFasaResponse(bodyBytes) {
+		return newHTTPError(resp, bodyBytes)
+	}
 	return xml.Unmarshal(bodyBytes, &v)
 }
 
+//isFasaResponse check is body a fasa_response xml document
+func isFasaResponse(body []byte) bool {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return false
+		}
+		if element, ok := token.(xml.StartElement); ok {
+			return element.Name.Local == "fasa_response"
+		}
+	}
+}
+
 //NewResourceAbstract Create new resource abstract
 func NewResourceAbstract(transport *Transport, config *Config) ResourceAbstract {
 	return ResourceAbstract{tr: transport, cfg: config}
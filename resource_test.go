@@ -1,8 +1,10 @@
 package fasapay
 
 import (
+	"errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
+	"net/http"
 	"testing"
 )
 
@@ -53,6 +55,40 @@ func (suite *ResourceAbstractTestSuite) TestMarshalRequestParams() {
 	assert.Equal(suite.T(), expected, string(result))
 }
 
+func (suite *ResourceAbstractTestSuite) TestUnmarshalResponseSuccess() {
+	rsp := BuildStubResponseFromFile(http.StatusOK, "stubs/accounts/balances/success.xml")
+	var result GetBalancesResponse
+	err := suite.testable.unmarshalResponse(rsp, &result)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "1234567", result.Id)
+}
+
+func (suite *ResourceAbstractTestSuite) TestUnmarshalResponseNonXml() {
+	rsp := BuildStubResponseFromFile(http.StatusOK, "stubs/errors/500.html")
+	var result GetBalancesResponse
+	err := suite.testable.unmarshalResponse(rsp, &result)
+	var httpErr *HTTPError
+	assert.True(suite.T(), errors.As(err, &httpErr))
+	assert.Equal(suite.T(), http.StatusOK, httpErr.StatusCode)
+	assert.Equal(suite.T(), "Bad request", httpErr.Body)
+}
+
+func (suite *ResourceAbstractTestSuite) TestUnmarshalResponseNon2xxStatus() {
+	rsp := BuildStubResponseFromFile(http.StatusServiceUnavailable, "stubs/accounts/balances/success.xml")
+	var result GetBalancesResponse
+	err := suite.testable.unmarshalResponse(rsp, &result)
+	var httpErr *HTTPError
+	assert.True(suite.T(), errors.As(err, &httpErr))
+	assert.Equal(suite.T(), http.StatusServiceUnavailable, httpErr.StatusCode)
+}
+
+func (suite *ResourceAbstractTestSuite) TestIsFasaResponse() {
+	assert.True(suite.T(), isFasaResponse([]byte(`<?xml version="1.0"?><fasa_response id="1"></fasa_response>`)))
+	assert.False(suite.T(), isFasaResponse([]byte(`<html><body>Bad gateway</body></html>`)))
+	assert.False(suite.T(), isFasaResponse([]byte(`Bad request`)))
+	assert.False(suite.T(), isFasaResponse(nil))
+}
+
 func TestResourceAbstractTestSuite(t *testing.T) {
 	suite.Run(t, new(ResourceAbstractTestSuite))
 }
@@ -0,0 +1,94 @@
+package fasapay
+
+import "errors"
+
+//ErrTransferNotProcessed transfer is not processed by FasaPay, because other transfer of the request is rejected
+var ErrTransferNotProcessed = errors.New("transfer is not processed because of other transfer failure")
+
+//TransferResultStatus type - outcome of the submitted transfer
+type TransferResultStatus string
+
+const (
+	//TransferResultStatusAccepted transfer is accepted by FasaPay
+	TransferResultStatusAccepted TransferResultStatus = "accepted"
+	//TransferResultStatusRejected transfer is rejected by FasaPay
+	TransferResultStatusRejected TransferResultStatus = "rejected"
+	//TransferResultStatusNotProcessed transfer is missing in the response (outcome is not confirmed, look it up before resubmitting)
+	TransferResultStatusNotProcessed TransferResultStatus = "not_processed"
+)
+
+//TransferResult struct - outcome of the single transfer of the request
+type TransferResult struct {
+	Id       string                        `json:"id"`
+	Status   TransferResultStatus          `json:"status"`
+	Transfer *CreateTransferRequestParams  `json:"transfer"`
+	Result   *CreateTransferResponseParams `json:"result,omitempty"` //accepted transfer (batch number, fee, total)
+	Error    *APIError                     `json:"error,omitempty"`  //rejection reason (error codes and attributes)
+}
+
+//IsSuccess method
+func (tr *TransferResult) IsSuccess() bool {
+	return tr.Status == TransferResultStatusAccepted
+}
+
+//Err method - failure of the transfer, nil if accepted
+func (tr *TransferResult) Err() error {
+	switch tr.Status {
+	case TransferResultStatusAccepted:
+		return nil
+	case TransferResultStatusRejected:
+		return tr.Error
+	default:
+		return ErrTransferNotProcessed
+	}
+}
+
+//correlateTransferResults Match response entries with submitted transfers.
+//Every accepted entry is matched with the first unmatched transfer of the same recipient, amount and currency,
+//so transfers missing in short or reordered response keep not processed status (their outcome is unknown).
+//Rejected transfer is matched by id of the errors element, if it can not be identified,
+//all transfers without accepted entry are considered rejected.
+func correlateTransferResults(transfers []*CreateTransferRequestParams, response *CreateTransferResponse) []*TransferResult {
+	results := make([]*TransferResult, len(transfers))
+	for index, transfer := range transfers {
+		results[index] = &TransferResult{Id: transfer.Id, Status: TransferResultStatusNotProcessed, Transfer: transfer}
+	}
+	apiErr := response.GetAPIError()
+	rejected := -1
+	if apiErr != nil {
+		for index, transfer := range transfers {
+			if (apiErr.Id != "" && transfer.Id == apiErr.Id) || (apiErr.Id == "" && len(transfers) == 1) {
+				rejected = index
+				results[index].Status = TransferResultStatusRejected
+				results[index].Error = apiErr
+				break
+			}
+		}
+	}
+	for _, entry := range response.Transfers {
+		for _, result := range results {
+			if result.Status == TransferResultStatusNotProcessed && isTransferResultOf(result.Transfer, entry) {
+				result.Status = TransferResultStatusAccepted
+				result.Result = entry
+				break
+			}
+		}
+	}
+	if apiErr != nil && rejected < 0 {
+		for _, result := range results {
+			if result.Status == TransferResultStatusNotProcessed {
+				result.Status = TransferResultStatusRejected
+				result.Error = apiErr
+			}
+		}
+	}
+	return results
+}
+
+//isTransferResultOf Check is response entry describes the transfer (recipient, amount and currency are equal)
+func isTransferResultOf(transfer *CreateTransferRequestParams, entry *CreateTransferResponseParams) bool {
+	return entry != nil &&
+		entry.To.Normalize() == transfer.To.Normalize() &&
+		CurrencyCode(entry.Currency).Normalize() == transfer.Currency.Normalize() &&
+		entry.Amount.Equal(transfer.Amount)
+}
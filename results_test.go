@@ -0,0 +1,143 @@
+package fasapay
+
+import (
+	"encoding/xml"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type TransferResultsTestSuite struct {
+	suite.Suite
+}
+
+//buildTransfers method
+func (suite *TransferResultsTestSuite) buildTransfers(ids ...string) []*CreateTransferRequestParams {
+	transfers := make([]*CreateTransferRequestParams, len(ids))
+	for i, id := range ids {
+		transfers[i] = &CreateTransferRequestParams{Id: id, To: "FP89680", Amount: MustParseAmount("1000"), Currency: CurrencyCodeIDR}
+	}
+	return transfers
+}
+
+//loadResponse method
+func (suite *TransferResultsTestSuite) loadResponse(path string) *CreateTransferResponse {
+	var response CreateTransferResponse
+	body, _ := LoadStubResponseData(path)
+	_ = xml.Unmarshal(body, &response)
+	return &response
+}
+
+func (suite *TransferResultsTestSuite) TestCorrelateSuccess() {
+	transfers := suite.buildTransfers("tid1")
+	results := correlateTransferResults(transfers, suite.loadResponse("stubs/transfers/transfer/success.xml"))
+	assert.Len(suite.T(), results, 1)
+	assert.Equal(suite.T(), "tid1", results[0].Id)
+	assert.Equal(suite.T(), TransferResultStatusAccepted, results[0].Status)
+	assert.True(suite.T(), results[0].IsSuccess())
+	assert.NoError(suite.T(), results[0].Err())
+	assert.Equal(suite.T(), transfers[0], results[0].Transfer)
+	assert.Equal(suite.T(), "TR2011071917277", results[0].Result.BatchNumber)
+}
+
+func (suite *TransferResultsTestSuite) TestCorrelateMissingEntries() {
+	results := correlateTransferResults(suite.buildTransfers("tid1", "tid2"), suite.loadResponse("stubs/transfers/transfer/success.xml"))
+	assert.Equal(suite.T(), TransferResultStatusAccepted, results[0].Status)
+	assert.Equal(suite.T(), TransferResultStatusNotProcessed, results[1].Status)
+	assert.Equal(suite.T(), ErrTransferNotProcessed, results[1].Err())
+}
+
+func (suite *TransferResultsTestSuite) TestCorrelateShortResponse() {
+	transfers := []*CreateTransferRequestParams{
+		{Id: "tid1", To: "FP00001", Amount: MustParseAmount("5"), Currency: CurrencyCodeIDR},
+		{Id: "tid2", To: "FP89680", Amount: MustParseAmount("1000"), Currency: CurrencyCodeIDR},
+	}
+	results := correlateTransferResults(transfers, suite.loadResponse("stubs/transfers/transfer/success.xml"))
+	assert.Equal(suite.T(), TransferResultStatusNotProcessed, results[0].Status)
+	assert.Nil(suite.T(), results[0].Result)
+	assert.Equal(suite.T(), TransferResultStatusAccepted, results[1].Status)
+	assert.Equal(suite.T(), "TR2011071917277", results[1].Result.BatchNumber)
+}
+
+func (suite *TransferResultsTestSuite) TestCorrelateReorderedResponse() {
+	transfers := []*CreateTransferRequestParams{
+		{Id: "tid1", To: "FP00001", Amount: MustParseAmount("5"), Currency: CurrencyCodeIDR},
+		{Id: "tid2", To: "FP00002", Amount: MustParseAmount("10"), Currency: CurrencyCodeUSD},
+	}
+	response := &CreateTransferResponse{Transfers: []*CreateTransferResponseParams{
+		{BatchNumber: "TR2", To: "FP00002", Amount: MustParseAmount("10.00"), Currency: "USD"},
+		{BatchNumber: "TR1", To: "fp00001", Amount: MustParseAmount("5.0"), Currency: "idr"},
+	}}
+	results := correlateTransferResults(transfers, response)
+	assert.Equal(suite.T(), TransferResultStatusAccepted, results[0].Status)
+	assert.Equal(suite.T(), "TR1", results[0].Result.BatchNumber)
+	assert.Equal(suite.T(), TransferResultStatusAccepted, results[1].Status)
+	assert.Equal(suite.T(), "TR2", results[1].Result.BatchNumber)
+}
+
+func (suite *TransferResultsTestSuite) TestCorrelateMismatchedEntry() {
+	transfers := suite.buildTransfers("tid1", "tid2", "tid3")
+	response := &CreateTransferResponse{Transfers: []*CreateTransferResponseParams{
+		{BatchNumber: "TR1", To: "FP89680", Amount: MustParseAmount("999"), Currency: "IDR"},
+		{BatchNumber: "TR2", To: "FP89680", Amount: MustParseAmount("1000"), Currency: "USD"},
+		{BatchNumber: "TR3", To: "FP89681", Amount: MustParseAmount("1000"), Currency: "IDR"},
+	}}
+	results := correlateTransferResults(transfers, response)
+	for _, result := range results {
+		assert.Equal(suite.T(), TransferResultStatusNotProcessed, result.Status)
+		assert.Nil(suite.T(), result.Result)
+	}
+}
+
+func (suite *TransferResultsTestSuite) TestCorrelateRejectedById() {
+	results := correlateTransferResults(suite.buildTransfers("tid1", "tid2", "tid3"), suite.loadResponse("stubs/transfers/transfer/error.xml"))
+	assert.Equal(suite.T(), TransferResultStatusNotProcessed, results[0].Status)
+	assert.Equal(suite.T(), TransferResultStatusNotProcessed, results[1].Status)
+	assert.Equal(suite.T(), TransferResultStatusRejected, results[2].Status)
+	assert.False(suite.T(), results[2].IsSuccess())
+	assert.Nil(suite.T(), results[2].Result)
+	assert.Equal(suite.T(), "tid3", results[2].Error.Id)
+	assert.True(suite.T(), results[2].Error.HasErrorCode(ErrorCodeUnknownRecipient))
+	assert.Equal(suite.T(), "to", results[2].Error.Errors[1].Attribute)
+	assert.True(suite.T(), errors.Is(results[2].Err(), ErrAmountOverLimit))
+}
+
+func (suite *TransferResultsTestSuite) TestCorrelateRejectedWithAccepted() {
+	response := suite.loadResponse("stubs/transfers/transfer/error.xml")
+	response.Transfers = suite.loadResponse("stubs/transfers/transfer/success.xml").Transfers
+	results := correlateTransferResults(suite.buildTransfers("tid3", "tid4"), response)
+	assert.Equal(suite.T(), TransferResultStatusRejected, results[0].Status)
+	assert.Equal(suite.T(), TransferResultStatusAccepted, results[1].Status)
+	assert.Equal(suite.T(), "TR2011071917277", results[1].Result.BatchNumber)
+}
+
+func (suite *TransferResultsTestSuite) TestCorrelateRejectedUnknownId() {
+	results := correlateTransferResults(suite.buildTransfers("tid1", "tid2"), suite.loadResponse("stubs/transfers/transfer/error.xml"))
+	assert.Equal(suite.T(), TransferResultStatusRejected, results[0].Status)
+	assert.Equal(suite.T(), TransferResultStatusRejected, results[1].Status)
+}
+
+func (suite *TransferResultsTestSuite) TestCorrelateRejectedUnknownIdWithAccepted() {
+	response := suite.loadResponse("stubs/transfers/transfer/error.xml")
+	response.Transfers = suite.loadResponse("stubs/transfers/transfer/success.xml").Transfers
+	transfers := suite.buildTransfers("tid1", "tid2")
+	transfers[1].To = "FP89681"
+	results := correlateTransferResults(transfers, response)
+	assert.Equal(suite.T(), TransferResultStatusAccepted, results[0].Status)
+	assert.Equal(suite.T(), "TR2011071917277", results[0].Result.BatchNumber)
+	assert.Nil(suite.T(), results[0].Error)
+	assert.Equal(suite.T(), TransferResultStatusRejected, results[1].Status)
+	assert.Equal(suite.T(), "tid3", results[1].Error.Id)
+}
+
+func (suite *TransferResultsTestSuite) TestCorrelateRejectedSingleWithoutId() {
+	response := suite.loadResponse("stubs/transfers/transfer/error.xml")
+	response.Errors.Id = ""
+	results := correlateTransferResults(suite.buildTransfers(""), response)
+	assert.Equal(suite.T(), TransferResultStatusRejected, results[0].Status)
+}
+
+func TestTransferResultsTestSuite(t *testing.T) {
+	suite.Run(t, new(TransferResultsTestSuite))
+}
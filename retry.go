@@ -0,0 +1,92 @@
+package fasapay
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+//RetryClassifier func - decide is failed attempt retryable
+type RetryClassifier func(resp *http.Response, err error) bool
+
+//RetryPolicy struct
+type RetryPolicy struct {
+	MaxAttempts    int             //max number of attempts including the first one
+	InitialBackoff time.Duration   //delay before the first retry
+	MaxBackoff     time.Duration   //max delay between attempts
+	Multiplier     float64         //backoff multiplier applied after each attempt
+	Jitter         float64         //random jitter fraction (0..1) applied to each delay
+	IsRetryable    RetryClassifier //retryable status/error classifier, DefaultRetryClassifier if nil
+}
+
+//NewRetryPolicy Create new retry policy with default parameters
+func NewRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 200 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+		IsRetryable:    DefaultRetryClassifier,
+	}
+}
+
+//DefaultRetryClassifier retry on network errors, 429 and 5xx status codes
+func DefaultRetryClassifier(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+}
+
+//attempts method
+func (rp *RetryPolicy) attempts() int {
+	if rp == nil || rp.MaxAttempts < 1 {
+		return 1
+	}
+	return rp.MaxAttempts
+}
+
+//isRetryable method
+func (rp *RetryPolicy) isRetryable(resp *http.Response, err error) bool {
+	if rp.IsRetryable == nil {
+		return DefaultRetryClassifier(resp, err)
+	}
+	return rp.IsRetryable(resp, err)
+}
+
+//backoff method - delay before retry number attempt (starts from 1)
+func (rp *RetryPolicy) backoff(attempt int) time.Duration {
+	delay := exponentialDelay(rp.InitialBackoff, rp.MaxBackoff, rp.Multiplier, attempt)
+	if rp.Jitter > 0 {
+		delay += delay * rp.Jitter * (2*rand.Float64() - 1)
+	}
+	return time.Duration(delay)
+}
+
+//exponentialDelay Delay of attempt number (starts from 1) growing by multiplier (at least 1) from initial up to max (if set)
+func exponentialDelay(initial time.Duration, max time.Duration, multiplier float64, attempt int) float64 {
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	delay := float64(initial) * math.Pow(multiplier, float64(attempt-1))
+	if max > 0 && delay > float64(max) {
+		delay = float64(max)
+	}
+	return delay
+}
+
+//wait method - wait before retry or return context error
+func (rp *RetryPolicy) wait(ctx context.Context, attempt int) error {
+	timer := time.NewTimer(rp.backoff(attempt))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
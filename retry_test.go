@@ -0,0 +1,79 @@
+package fasapay
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type RetryPolicyTestSuite struct {
+	suite.Suite
+}
+
+func (suite *RetryPolicyTestSuite) TestNewRetryPolicy() {
+	result := NewRetryPolicy()
+	assert.Equal(suite.T(), 3, result.MaxAttempts)
+	assert.Equal(suite.T(), 200*time.Millisecond, result.InitialBackoff)
+	assert.Equal(suite.T(), 5*time.Second, result.MaxBackoff)
+	assert.Equal(suite.T(), float64(2), result.Multiplier)
+	assert.NotNil(suite.T(), result.IsRetryable)
+}
+
+func (suite *RetryPolicyTestSuite) TestAttempts() {
+	var policy *RetryPolicy
+	assert.Equal(suite.T(), 1, policy.attempts())
+	policy = &RetryPolicy{}
+	assert.Equal(suite.T(), 1, policy.attempts())
+	policy.MaxAttempts = 5
+	assert.Equal(suite.T(), 5, policy.attempts())
+}
+
+func (suite *RetryPolicyTestSuite) TestBackoffWithoutJitter() {
+	policy := &RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: 300 * time.Millisecond, Multiplier: 2}
+	assert.Equal(suite.T(), 100*time.Millisecond, policy.backoff(1))
+	assert.Equal(suite.T(), 200*time.Millisecond, policy.backoff(2))
+	assert.Equal(suite.T(), 300*time.Millisecond, policy.backoff(3))
+}
+
+func (suite *RetryPolicyTestSuite) TestBackoffWithJitter() {
+	policy := &RetryPolicy{InitialBackoff: 100 * time.Millisecond, Multiplier: 2, Jitter: 0.5}
+	for i := 0; i < 10; i++ {
+		result := policy.backoff(1)
+		assert.GreaterOrEqual(suite.T(), int64(result), int64(50*time.Millisecond))
+		assert.LessOrEqual(suite.T(), int64(result), int64(150*time.Millisecond))
+	}
+}
+
+func (suite *RetryPolicyTestSuite) TestDefaultRetryClassifier() {
+	assert.True(suite.T(), DefaultRetryClassifier(nil, errors.New("connection reset by peer")))
+	assert.False(suite.T(), DefaultRetryClassifier(nil, context.Canceled))
+	assert.False(suite.T(), DefaultRetryClassifier(nil, context.DeadlineExceeded))
+	assert.True(suite.T(), DefaultRetryClassifier(&http.Response{StatusCode: http.StatusServiceUnavailable}, nil))
+	assert.True(suite.T(), DefaultRetryClassifier(&http.Response{StatusCode: http.StatusTooManyRequests}, nil))
+	assert.False(suite.T(), DefaultRetryClassifier(&http.Response{StatusCode: http.StatusBadRequest}, nil))
+	assert.False(suite.T(), DefaultRetryClassifier(&http.Response{StatusCode: http.StatusOK}, nil))
+}
+
+func (suite *RetryPolicyTestSuite) TestIsRetryableCustomClassifier() {
+	policy := &RetryPolicy{IsRetryable: func(resp *http.Response, err error) bool {
+		return resp != nil && resp.StatusCode == http.StatusBadRequest
+	}}
+	assert.True(suite.T(), policy.isRetryable(&http.Response{StatusCode: http.StatusBadRequest}, nil))
+	assert.False(suite.T(), policy.isRetryable(&http.Response{StatusCode: http.StatusServiceUnavailable}, nil))
+}
+
+func (suite *RetryPolicyTestSuite) TestWaitContextCanceled() {
+	policy := &RetryPolicy{InitialBackoff: time.Second}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := policy.wait(ctx, 1)
+	assert.Equal(suite.T(), context.Canceled, err)
+}
+
+func TestRetryPolicyTestSuite(t *testing.T) {
+	suite.Run(t, new(RetryPolicyTestSuite))
+}
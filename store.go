@@ -0,0 +1,317 @@
+package fasapay
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+//TransferState type - lifecycle state of the stored transfer
+type TransferState string
+
+const (
+	//TransferStatePending transfer is stored, but is not submitted yet
+	TransferStatePending TransferState = "pending"
+	//TransferStateSubmitted transfer is submitted, the outcome of submission is unknown
+	TransferStateSubmitted TransferState = "submitted"
+	//TransferStateFinished transfer is accepted by FasaPay
+	TransferStateFinished TransferState = "finished"
+	//TransferStateFailed transfer is rejected by FasaPay
+	TransferStateFailed TransferState = "failed"
+)
+
+//IsInFlight method - is outcome of the transfer unknown
+func (ts TransferState) IsInFlight() bool {
+	return ts == TransferStatePending || ts == TransferStateSubmitted
+}
+
+//TransferRecord struct - stored transfer with its lifecycle state
+type TransferRecord struct {
+	Ref       string                        `json:"ref"`
+	Transfer  *CreateTransferRequestParams  `json:"transfer"`
+	State     TransferState                 `json:"state"`
+	Result    *CreateTransferResponseParams `json:"result,omitempty"`
+	Error     string                        `json:"error,omitempty"`
+	CreatedAt time.Time                     `json:"created_at"`
+	UpdatedAt time.Time                     `json:"updated_at"`
+}
+
+//clone method - copy of the record not sharing transfer and result with the original
+func (r TransferRecord) clone() TransferRecord {
+	if r.Transfer != nil {
+		transfer := *r.Transfer
+		r.Transfer = &transfer
+	}
+	if r.Result != nil {
+		result := *r.Result
+		r.Result = &result
+	}
+	return r
+}
+
+//TransferStore interface - outbox of transfers used for idempotent and crash-safe submission
+type TransferStore interface {
+	//Load stored transfer by reference code, false if transfer is not stored
+	Load(ref string) (*TransferRecord, bool, error)
+	//SavePending store new transfer before its submission
+	SavePending(record *TransferRecord) error
+	//MarkSubmitted mark transfer as sent to FasaPay
+	MarkSubmitted(ref string) error
+	//MarkFinished mark transfer as accepted by FasaPay
+	MarkFinished(ref string, result *CreateTransferResponseParams) error
+	//MarkFailed mark transfer as rejected by FasaPay
+	MarkFailed(ref string, reason string) error
+	//ListStuck list pending and submitted transfers not updated for the given duration (oldest first)
+	ListStuck(olderThan time.Duration) ([]*TransferRecord, error)
+}
+
+//transferRecordChange func - change of the stored record, ok is false if record is not stored yet
+type transferRecordChange func(record *TransferRecord, ok bool) error
+
+//InMemoryTransferStore struct - transfer store living in process memory (transfers and results are copied on save and load)
+type InMemoryTransferStore struct {
+	mu      sync.Mutex
+	records map[string]TransferRecord
+	now     func() time.Time
+}
+
+//NewInMemoryTransferStore Create new in-memory transfer store
+func NewInMemoryTransferStore() *InMemoryTransferStore {
+	return &InMemoryTransferStore{records: map[string]TransferRecord{}, now: time.Now}
+}
+
+//Load method
+func (s *InMemoryTransferStore) Load(ref string) (*TransferRecord, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record, ok := s.records[ref]
+	if !ok {
+		return nil, false, nil
+	}
+	record = record.clone()
+	return &record, true, nil
+}
+
+//SavePending method
+func (s *InMemoryTransferStore) SavePending(record *TransferRecord) error {
+	return s.change(record.Ref, savePendingChange(record), nil)
+}
+
+//MarkSubmitted method
+func (s *InMemoryTransferStore) MarkSubmitted(ref string) error {
+	return s.change(ref, markSubmittedChange(ref), nil)
+}
+
+//MarkFinished method
+func (s *InMemoryTransferStore) MarkFinished(ref string, result *CreateTransferResponseParams) error {
+	return s.change(ref, markFinishedChange(ref, result), nil)
+}
+
+//MarkFailed method
+func (s *InMemoryTransferStore) MarkFailed(ref string, reason string) error {
+	return s.change(ref, markFailedChange(ref, reason), nil)
+}
+
+//ListStuck method
+func (s *InMemoryTransferStore) ListStuck(olderThan time.Duration) ([]*TransferRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	deadline := s.now().Add(-olderThan)
+	var result []*TransferRecord
+	for _, record := range s.records {
+		if record.State.IsInFlight() && !record.UpdatedAt.After(deadline) {
+			stuck := record.clone()
+			result = append(result, &stuck)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+	return result, nil
+}
+
+//change method - apply change to the record, persist it and keep in memory
+func (s *InMemoryTransferStore) change(ref string, change transferRecordChange, persist func(record *TransferRecord) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record, ok := s.records[ref]
+	if err := change(&record, ok); err != nil {
+		return err
+	}
+	record.UpdatedAt = s.now()
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = record.UpdatedAt
+	}
+	if persist != nil {
+		if err := persist(&record); err != nil {
+			return err
+		}
+	}
+	s.records[ref] = record
+	return nil
+}
+
+//restore method - keep persisted record in memory as is
+func (s *InMemoryTransferStore) restore(record TransferRecord) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[record.Ref] = record
+}
+
+//savePendingChange Build change storing new pending transfer
+func savePendingChange(pending *TransferRecord) transferRecordChange {
+	return func(record *TransferRecord, ok bool) error {
+		if ok {
+			return fmt.Errorf(`transfer ref "%s" is already stored`, pending.Ref)
+		}
+		*record = pending.clone()
+		record.State = TransferStatePending
+		return nil
+	}
+}
+
+//markSubmittedChange Build change marking transfer as submitted
+func markSubmittedChange(ref string) transferRecordChange {
+	return func(record *TransferRecord, ok bool) error {
+		if !ok {
+			return fmt.Errorf(`transfer ref "%s" is not stored`, ref)
+		}
+		record.State = TransferStateSubmitted
+		record.Error = ""
+		return nil
+	}
+}
+
+//markFinishedChange Build change marking transfer as finished
+func markFinishedChange(ref string, result *CreateTransferResponseParams) transferRecordChange {
+	return func(record *TransferRecord, ok bool) error {
+		if !ok {
+			return fmt.Errorf(`transfer ref "%s" is not stored`, ref)
+		}
+		record.State = TransferStateFinished
+		record.Result = nil
+		if result != nil {
+			stored := *result
+			record.Result = &stored
+		}
+		record.Error = ""
+		return nil
+	}
+}
+
+//markFailedChange Build change marking transfer as failed
+func markFailedChange(ref string, reason string) transferRecordChange {
+	return func(record *TransferRecord, ok bool) error {
+		if !ok {
+			return fmt.Errorf(`transfer ref "%s" is not stored`, ref)
+		}
+		record.State = TransferStateFailed
+		record.Error = reason
+		return nil
+	}
+}
+
+//FileTransferStore struct - append-only JSON lines file transfer store for single node services.
+//Every change is appended as the full record snapshot, the latest snapshot of the ref wins on load.
+type FileTransferStore struct {
+	file   *os.File
+	memory *InMemoryTransferStore
+}
+
+//NewFileTransferStore Open (or create) JSON lines file transfer store and load its records
+func NewFileTransferStore(path string) (*FileTransferStore, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("NewFileTransferStore error: %w", err)
+	}
+	store := &FileTransferStore{file: file, memory: NewInMemoryTransferStore()}
+	if err = store.load(); err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("NewFileTransferStore error: %w", err)
+	}
+	return store, nil
+}
+
+//Load method
+func (s *FileTransferStore) Load(ref string) (*TransferRecord, bool, error) {
+	return s.memory.Load(ref)
+}
+
+//SavePending method
+func (s *FileTransferStore) SavePending(record *TransferRecord) error {
+	return s.memory.change(record.Ref, savePendingChange(record), s.append)
+}
+
+//MarkSubmitted method
+func (s *FileTransferStore) MarkSubmitted(ref string) error {
+	return s.memory.change(ref, markSubmittedChange(ref), s.append)
+}
+
+//MarkFinished method
+func (s *FileTransferStore) MarkFinished(ref string, result *CreateTransferResponseParams) error {
+	return s.memory.change(ref, markFinishedChange(ref, result), s.append)
+}
+
+//MarkFailed method
+func (s *FileTransferStore) MarkFailed(ref string, reason string) error {
+	return s.memory.change(ref, markFailedChange(ref, reason), s.append)
+}
+
+//ListStuck method
+func (s *FileTransferStore) ListStuck(olderThan time.Duration) ([]*TransferRecord, error) {
+	return s.memory.ListStuck(olderThan)
+}
+
+//Close method - close the store file
+func (s *FileTransferStore) Close() error {
+	return s.file.Close()
+}
+
+//append method - append record snapshot to the file and flush it to disk
+func (s *FileTransferStore) append(record *TransferRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("FileTransferStore.append error: %w", err)
+	}
+	if _, err = s.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("FileTransferStore.append error: %w", err)
+	}
+	if err = s.file.Sync(); err != nil {
+		return fmt.Errorf("FileTransferStore.append error: %w", err)
+	}
+	return nil
+}
+
+//load method - replay records from the file, truncated last line (interrupted write) is dropped
+func (s *FileTransferStore) load() error {
+	reader := bufio.NewReader(s.file)
+	var offset int64
+	for number := 1; ; number++ {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			if len(line) > 0 {
+				return s.file.Truncate(offset)
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		offset += int64(len(line))
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		var record TransferRecord
+		if err = json.Unmarshal(line, &record); err != nil {
+			return fmt.Errorf("line %d: %w", number, err)
+		}
+		s.memory.restore(record)
+	}
+}
@@ -0,0 +1,234 @@
+package fasapay
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type InMemoryTransferStoreTestSuite struct {
+	suite.Suite
+	testable *InMemoryTransferStore
+	now      time.Time
+}
+
+func (suite *InMemoryTransferStoreTestSuite) SetupTest() {
+	suite.now = BuildStubDateTime()
+	suite.testable = NewInMemoryTransferStore()
+	suite.testable.now = func() time.Time {
+		return suite.now
+	}
+}
+
+func (suite *InMemoryTransferStoreTestSuite) TestLifecycle() {
+	_, ok, err := suite.testable.Load("ref")
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), ok)
+
+	transfer := &CreateTransferRequestParams{To: "FP89680", Amount: MustParseAmount("1000"), Currency: CurrencyCodeIDR, Ref: "ref"}
+	assert.NoError(suite.T(), suite.testable.SavePending(&TransferRecord{Ref: "ref", Transfer: transfer}))
+	record, ok, _ := suite.testable.Load("ref")
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), TransferStatePending, record.State)
+	assert.Equal(suite.T(), suite.now, record.CreatedAt)
+	assert.Equal(suite.T(), transfer, record.Transfer)
+
+	suite.now = suite.now.Add(time.Minute)
+	assert.NoError(suite.T(), suite.testable.MarkSubmitted("ref"))
+	record, _, _ = suite.testable.Load("ref")
+	assert.Equal(suite.T(), TransferStateSubmitted, record.State)
+	assert.Equal(suite.T(), suite.now, record.UpdatedAt)
+	assert.Equal(suite.T(), BuildStubDateTime(), record.CreatedAt)
+
+	assert.NoError(suite.T(), suite.testable.MarkFailed("ref", "NOT ACCEPTABLE TRANSFER"))
+	record, _, _ = suite.testable.Load("ref")
+	assert.Equal(suite.T(), TransferStateFailed, record.State)
+	assert.Equal(suite.T(), "NOT ACCEPTABLE TRANSFER", record.Error)
+
+	assert.NoError(suite.T(), suite.testable.MarkFinished("ref", &CreateTransferResponseParams{BatchNumber: "TR1"}))
+	record, _, _ = suite.testable.Load("ref")
+	assert.Equal(suite.T(), TransferStateFinished, record.State)
+	assert.Equal(suite.T(), "TR1", record.Result.BatchNumber)
+	assert.Empty(suite.T(), record.Error)
+}
+
+func (suite *InMemoryTransferStoreTestSuite) TestLoadReturnsCopy() {
+	_ = suite.testable.SavePending(&TransferRecord{Ref: "ref"})
+	record, _, _ := suite.testable.Load("ref")
+	record.State = TransferStateFinished
+	record, _, _ = suite.testable.Load("ref")
+	assert.Equal(suite.T(), TransferStatePending, record.State)
+}
+
+func (suite *InMemoryTransferStoreTestSuite) TestStoresCopyOfTransfer() {
+	transfer := &CreateTransferRequestParams{To: "FP89680", Amount: MustParseAmount("1000"), Currency: CurrencyCodeIDR, Ref: "ref"}
+	_ = suite.testable.SavePending(&TransferRecord{Ref: "ref", Transfer: transfer})
+	result := &CreateTransferResponseParams{BatchNumber: "TR1"}
+	_ = suite.testable.MarkFinished("ref", result)
+	transfer.To = "FP00001"
+	transfer.Amount = MustParseAmount("5")
+	result.BatchNumber = "TR2"
+
+	record, _, _ := suite.testable.Load("ref")
+	assert.Equal(suite.T(), AccountNumber("FP89680"), record.Transfer.To)
+	assert.Equal(suite.T(), "1000", record.Transfer.Amount.String())
+	assert.Equal(suite.T(), "TR1", record.Result.BatchNumber)
+	assert.False(suite.T(), isSameTransfer(record.Transfer, transfer))
+
+	record.Transfer.To = "FP00002"
+	record.Result.BatchNumber = "TR3"
+	record, _, _ = suite.testable.Load("ref")
+	assert.Equal(suite.T(), AccountNumber("FP89680"), record.Transfer.To)
+	assert.Equal(suite.T(), "TR1", record.Result.BatchNumber)
+}
+
+func (suite *InMemoryTransferStoreTestSuite) TestErrors() {
+	assert.Equal(suite.T(), `transfer ref "ref" is not stored`, suite.testable.MarkSubmitted("ref").Error())
+	assert.Equal(suite.T(), `transfer ref "ref" is not stored`, suite.testable.MarkFinished("ref", nil).Error())
+	assert.Equal(suite.T(), `transfer ref "ref" is not stored`, suite.testable.MarkFailed("ref", "").Error())
+	_ = suite.testable.SavePending(&TransferRecord{Ref: "ref"})
+	assert.Equal(suite.T(), `transfer ref "ref" is already stored`, suite.testable.SavePending(&TransferRecord{Ref: "ref"}).Error())
+}
+
+func (suite *InMemoryTransferStoreTestSuite) TestListStuck() {
+	_ = suite.testable.SavePending(&TransferRecord{Ref: "ref-1"})
+	suite.now = suite.now.Add(time.Minute)
+	_ = suite.testable.SavePending(&TransferRecord{Ref: "ref-2"})
+	_ = suite.testable.MarkSubmitted("ref-2")
+	_ = suite.testable.SavePending(&TransferRecord{Ref: "ref-3"})
+	_ = suite.testable.MarkFinished("ref-3", nil)
+	_ = suite.testable.SavePending(&TransferRecord{Ref: "ref-4"})
+	_ = suite.testable.MarkFailed("ref-4", "")
+	suite.now = suite.now.Add(time.Minute)
+
+	result, err := suite.testable.ListStuck(0)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), result, 2)
+	assert.Equal(suite.T(), "ref-1", result[0].Ref)
+	assert.Equal(suite.T(), "ref-2", result[1].Ref)
+
+	result, _ = suite.testable.ListStuck(90 * time.Second)
+	assert.Len(suite.T(), result, 1)
+	assert.Equal(suite.T(), "ref-1", result[0].Ref)
+}
+
+func (suite *InMemoryTransferStoreTestSuite) TestTransferStateIsInFlight() {
+	assert.True(suite.T(), TransferStatePending.IsInFlight())
+	assert.True(suite.T(), TransferStateSubmitted.IsInFlight())
+	assert.False(suite.T(), TransferStateFinished.IsInFlight())
+	assert.False(suite.T(), TransferStateFailed.IsInFlight())
+}
+
+func TestInMemoryTransferStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(InMemoryTransferStoreTestSuite))
+}
+
+type FileTransferStoreTestSuite struct {
+	suite.Suite
+	dir  string
+	path string
+}
+
+func (suite *FileTransferStoreTestSuite) SetupTest() {
+	suite.dir, _ = ioutil.TempDir("", "fasapay")
+	suite.path = filepath.Join(suite.dir, "transfers.jsonl")
+}
+
+func (suite *FileTransferStoreTestSuite) TearDownTest() {
+	_ = os.RemoveAll(suite.dir)
+}
+
+func (suite *FileTransferStoreTestSuite) TestPersistence() {
+	store, err := NewFileTransferStore(suite.path)
+	assert.NoError(suite.T(), err)
+	transfer := &CreateTransferRequestParams{To: "FP89680", Amount: MustParseAmount("1000.50"), Currency: CurrencyCodeIDR, Ref: "ref-1"}
+	assert.NoError(suite.T(), store.SavePending(&TransferRecord{Ref: "ref-1", Transfer: transfer}))
+	assert.NoError(suite.T(), store.MarkSubmitted("ref-1"))
+	assert.NoError(suite.T(), store.SavePending(&TransferRecord{Ref: "ref-2", Transfer: transfer}))
+	assert.NoError(suite.T(), store.MarkFinished("ref-2", &CreateTransferResponseParams{BatchNumber: "TR1"}))
+	assert.NoError(suite.T(), store.Close())
+
+	body, _ := ioutil.ReadFile(suite.path)
+	assert.Equal(suite.T(), 4, len(splitLines(body)))
+
+	store, err = NewFileTransferStore(suite.path)
+	assert.NoError(suite.T(), err)
+	defer store.Close()
+	record, ok, _ := store.Load("ref-1")
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), TransferStateSubmitted, record.State)
+	assert.Equal(suite.T(), AccountNumber("FP89680"), record.Transfer.To)
+	assert.Equal(suite.T(), "1000.50", record.Transfer.Amount.String())
+	record, _, _ = store.Load("ref-2")
+	assert.Equal(suite.T(), TransferStateFinished, record.State)
+	assert.Equal(suite.T(), "TR1", record.Result.BatchNumber)
+
+	stuck, _ := store.ListStuck(0)
+	assert.Len(suite.T(), stuck, 1)
+	assert.Equal(suite.T(), "ref-1", stuck[0].Ref)
+}
+
+func (suite *FileTransferStoreTestSuite) TestMarkFinishedNonAccountResult() {
+	store, err := NewFileTransferStore(suite.path)
+	assert.NoError(suite.T(), err)
+	defer store.Close()
+	transfer := &CreateTransferRequestParams{To: "FP89680", Amount: MustParseAmount("1000"), Currency: CurrencyCodeIDR, Ref: "ref-1"}
+	assert.NoError(suite.T(), store.SavePending(&TransferRecord{Ref: "ref-1", Transfer: transfer}))
+	result := &CreateTransferResponseParams{BatchNumber: "TR1", From: "BCA 123", To: "FP89680"}
+	assert.NoError(suite.T(), store.MarkFinished("ref-1", result))
+	record, _, _ := store.Load("ref-1")
+	assert.Equal(suite.T(), AccountNumber("BCA 123"), record.Result.From)
+}
+
+func (suite *FileTransferStoreTestSuite) TestTruncatedLastLine() {
+	content := `{"ref":"ref-1","state":"pending"}` + "\n" + `{"ref":"ref-1","sta`
+	_ = ioutil.WriteFile(suite.path, []byte(content), 0600)
+
+	store, err := NewFileTransferStore(suite.path)
+	assert.NoError(suite.T(), err)
+	record, ok, _ := store.Load("ref-1")
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), TransferStatePending, record.State)
+	assert.NoError(suite.T(), store.MarkSubmitted("ref-1"))
+	assert.NoError(suite.T(), store.Close())
+
+	store, err = NewFileTransferStore(suite.path)
+	assert.NoError(suite.T(), err)
+	defer store.Close()
+	record, _, _ = store.Load("ref-1")
+	assert.Equal(suite.T(), TransferStateSubmitted, record.State)
+}
+
+func (suite *FileTransferStoreTestSuite) TestCorruptedLine() {
+	content := `foo` + "\n" + `{"ref":"ref-1","state":"pending"}` + "\n"
+	_ = ioutil.WriteFile(suite.path, []byte(content), 0600)
+
+	_, err := NewFileTransferStore(suite.path)
+	assert.Error(suite.T(), err)
+	assert.Contains(suite.T(), err.Error(), "NewFileTransferStore error: line 1:")
+}
+
+func (suite *FileTransferStoreTestSuite) TestOpenError() {
+	_, err := NewFileTransferStore(filepath.Join(suite.dir, "missing", "transfers.jsonl"))
+	assert.Error(suite.T(), err)
+}
+
+//splitLines Split non-empty lines
+func splitLines(body []byte) []string {
+	var result []string
+	for _, line := range strings.Split(string(body), "\n") {
+		if line != "" {
+			result = append(result, line)
+		}
+	}
+	return result
+}
+
+func TestFileTransferStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(FileTransferStoreTestSuite))
+}
@@ -0,0 +1,48 @@
+package fasapay
+
+import "context"
+
+//Tracer interface - spans factory (implement it as an adapter to your tracing library)
+type Tracer interface {
+	StartSpan(ctx context.Context, operation Operation, attributes *SpanAttributes) (context.Context, Span)
+}
+
+//Span interface
+type Span interface {
+	End(result *SpanResult)
+}
+
+//SpanAttributes struct - span attributes known before the request is sent
+type SpanAttributes struct {
+	RequestId string //fasa_request id attribute
+	Items     int    //number of items in the request batch (balances, accounts, transfers, details)
+}
+
+//SpanResult struct - span attributes known after the response is received
+type SpanResult struct {
+	Endpoint   string //endpoint which served the request
+	StatusCode int    //http response status code (0 if there is no response)
+	ErrorCode  uint64 //FasaPay error code (0 if successful)
+	Err        error  //operation error
+}
+
+//TracingMiddleware Create middleware which wraps every API operation into a span
+func TracingMiddleware(tracer Tracer) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(ctx context.Context, rt *RoundTrip) error {
+			ctx, span := tracer.StartSpan(ctx, rt.Operation, &SpanAttributes{RequestId: rt.RequestId, Items: rt.Items})
+			err := next(ctx, rt)
+			result := &SpanResult{Endpoint: rt.Endpoint, Err: err}
+			if rt.Response != nil {
+				result.StatusCode = rt.Response.StatusCode
+			}
+			if err == nil && rt.Result != nil && !rt.Result.IsSuccess() {
+				apiErr := rt.Result.GetAPIError()
+				result.ErrorCode = apiErr.Code
+				result.Err = apiErr
+			}
+			span.End(result)
+			return err
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package fasapay
+
+import (
+	"context"
+	"errors"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"testing"
+)
+
+type stubSpanContextKey struct{}
+
+type stubSpan struct {
+	operation  Operation
+	attributes *SpanAttributes
+	result     *SpanResult
+}
+
+func (s *stubSpan) End(result *SpanResult) {
+	s.result = result
+}
+
+type stubTracer struct {
+	spans []*stubSpan
+}
+
+func (t *stubTracer) StartSpan(ctx context.Context, operation Operation, attributes *SpanAttributes) (context.Context, Span) {
+	span := &stubSpan{operation: operation, attributes: attributes}
+	t.spans = append(t.spans, span)
+	return context.WithValue(ctx, stubSpanContextKey{}, span), span
+}
+
+type TracingTestSuite struct {
+	suite.Suite
+	cfg      *Config
+	ctx      context.Context
+	tracer   *stubTracer
+	testable *TransfersResource
+}
+
+func (suite *TracingTestSuite) SetupTest() {
+	cfg := BuildStubConfig()
+	transport := BuildStubHttpTransport()
+	suite.tracer = &stubTracer{}
+	transport.tracer = suite.tracer
+	suite.cfg = cfg
+	suite.ctx = context.Background()
+	suite.testable = &TransfersResource{ResourceAbstract: NewResourceAbstract(transport, cfg)}
+	httpmock.Activate()
+}
+
+func (suite *TracingTestSuite) TearDownTest() {
+	httpmock.DeactivateAndReset()
+}
+
+func (suite *TracingTestSuite) TestSpanSuccess() {
+	body, _ := LoadStubResponseData("stubs/transfers/details/success.xml")
+	httpmock.RegisterResponder(http.MethodPost, suite.cfg.Uri, httpmock.NewBytesResponder(http.StatusOK, body))
+	var spanFromContext interface{}
+	suite.testable.tr.middlewares = []Middleware{func(next RoundTripFunc) RoundTripFunc {
+		return func(ctx context.Context, rt *RoundTrip) error {
+			spanFromContext = ctx.Value(stubSpanContextKey{})
+			return next(ctx, rt)
+		}
+	}}
+
+	var detail1 GetDetailsRequestDetailParamsString = "TR0000000001"
+	var detail2 GetDetailsRequestDetailParamsString = "TR0000000002"
+	attributes := &RequestParamsAttributes{Id: "1234567", DateTime: BuildStubDateTime()}
+	_, _, err := suite.testable.GetDetails([]GetDetailsDetailParamsInterface{&detail1, &detail2}, suite.ctx, attributes)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), suite.tracer.spans, 1)
+	span := suite.tracer.spans[0]
+	assert.Equal(suite.T(), span, spanFromContext)
+	assert.Equal(suite.T(), OperationGetDetails, span.operation)
+	assert.Equal(suite.T(), "1234567", span.attributes.RequestId)
+	assert.Equal(suite.T(), 2, span.attributes.Items)
+	assert.Equal(suite.T(), SandboxAPIUrl, span.result.Endpoint)
+	assert.Equal(suite.T(), http.StatusOK, span.result.StatusCode)
+	assert.Equal(suite.T(), uint64(0), span.result.ErrorCode)
+	assert.NoError(suite.T(), span.result.Err)
+}
+
+func (suite *TracingTestSuite) TestSpanAPIError() {
+	body, _ := LoadStubResponseData("stubs/transfers/transfer/error.xml")
+	httpmock.RegisterResponder(http.MethodPost, suite.cfg.Uri, httpmock.NewBytesResponder(http.StatusOK, body))
+
+	transfer := &CreateTransferRequestParams{To: "FP89680", Amount: MustParseAmount("1000"), Currency: CurrencyCodeIDR}
+	_, _, err := suite.testable.CreateTransfer([]*CreateTransferRequestParams{transfer, transfer, transfer}, suite.ctx, nil)
+	assert.Error(suite.T(), err)
+	span := suite.tracer.spans[0]
+	assert.Equal(suite.T(), OperationCreateTransfer, span.operation)
+	assert.Equal(suite.T(), 3, span.attributes.Items)
+	assert.Equal(suite.T(), ErrorCodeNotAcceptableTransfer, span.result.ErrorCode)
+	assert.True(suite.T(), errors.Is(span.result.Err, ErrNotAcceptableTransfer))
+}
+
+func (suite *TracingTestSuite) TestSpanNetworkError() {
+	httpmock.RegisterResponder(http.MethodPost, suite.cfg.Uri, httpmock.NewErrorResponder(errors.New("connection reset by peer")))
+
+	_, _, err := suite.testable.GetHistory(&GetHistoryRequestParams{}, suite.ctx, nil)
+	assert.Error(suite.T(), err)
+	span := suite.tracer.spans[0]
+	assert.Equal(suite.T(), OperationGetHistory, span.operation)
+	assert.Equal(suite.T(), 1, span.attributes.Items)
+	assert.Equal(suite.T(), 0, span.result.StatusCode)
+	assert.Error(suite.T(), span.result.Err)
+}
+
+func TestTracingTestSuite(t *testing.T) {
+	suite.Run(t, new(TracingTestSuite))
+}
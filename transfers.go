@@ -3,16 +3,18 @@ package fasapay
 import (
 	"context"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"net/http"
+	"unicode/utf8"
 )
 
 //CreateTransferRequestParams struct
 type CreateTransferRequestParams struct {
-	XMLName  xml.Name           `xml:"transfer"`
+	XMLName  xml.Name           `xml:"transfer" json:"-"`
 	Id       string             `xml:"id,attr,omitempty" json:"id"`        //id transfer for marking the transfer (max 50 character)
-	To       string             `xml:"to" json:"to"`                       //is the FasaPay account target format: FPnnnnn
-	Amount   float64            `xml:"amount" json:"amount"`               //is the amount of the transferred fund. with point (.) as the decimal separator
+	To       AccountNumber      `xml:"to" json:"to"`                       //is the FasaPay account target format: FPnnnnn
+	Amount   Amount             `xml:"amount" json:"amount"`               //is the amount of the transferred fund. with point (.) as the decimal separator
 	Currency CurrencyCode       `xml:"currency" json:"currency"`           //is the currency used in the transfer (IDR | USD)
 	FeeMode  TransactionFeeMode `xml:"fee_mode,omitempty" json:"fee_mode"` //is Fee Mode used in the transfer. default to FiR (FiR | FiS)
 	Note     string             `xml:"note,omitempty" json:"note"`         //is note of the transfer (max 255 character)
@@ -21,15 +23,68 @@ type CreateTransferRequestParams struct {
 
 //isValid method
 func (ctr *CreateTransferRequestParams) isValid() error {
-	var err error
+	ve := &ValidationError{}
+	ctr.validate(0, ve)
+	return ve.errorOrNil()
+}
+
+//validate method - collect all violations of the transfer params
+func (ctr *CreateTransferRequestParams) validate(index int, ve *ValidationError) {
+	if utf8.RuneCountInString(ctr.Id) > TransferIdMaxLength {
+		ve.add(index, "id", `parameter "id" is longer than %d characters`, TransferIdMaxLength)
+	}
 	if ctr.To == "" {
-		err = fmt.Errorf(`parameter "to" is empty`)
-	} else if ctr.Currency == "" {
-		err = fmt.Errorf(`parameter "currency" is empty`)
-	} else if ctr.Amount == 0 {
-		err = fmt.Errorf(`parameter "amount" is empty`)
+		ve.add(index, "to", `parameter "to" is empty`)
+	} else if ctr.To.Validate() != nil {
+		ve.add(index, "to", `parameter "to" has invalid format, FPnnnnn expected`)
+	}
+	if ctr.FeeMode != "" && ctr.FeeMode != TransactionFeeModeFiR && ctr.FeeMode != TransactionFeeModeFiS {
+		ve.add(index, "fee_mode", `parameter "fee_mode" is not supported`)
+	}
+	if utf8.RuneCountInString(ctr.Note) > TransferNoteMaxLength {
+		ve.add(index, "note", `parameter "note" is longer than %d characters`, TransferNoteMaxLength)
+	}
+	if utf8.RuneCountInString(ctr.Ref) > TransferRefMaxLength {
+		ve.add(index, "ref", `parameter "ref" is longer than %d characters`, TransferRefMaxLength)
+	}
+	var currency *Currency
+	if ctr.Currency == "" {
+		ve.add(index, "currency", `parameter "currency" is empty`)
+	} else if cur, ok := LookupCurrency(ctr.Currency); !ok {
+		ve.add(index, "currency", `parameter "currency" is not supported`)
+	} else {
+		currency = cur
+	}
+	if ctr.Amount.IsZero() {
+		ve.add(index, "amount", `parameter "amount" is empty`)
+	} else if ctr.Amount.IsNegative() {
+		ve.add(index, "amount", `parameter "amount" must be positive`)
+	} else if currency != nil {
+		if ctr.Amount.Decimals() > currency.Precision {
+			ve.add(index, "amount", `parameter "amount" has more than %d decimal places`, currency.Precision)
+		} else if !currency.MinTransfer.IsZero() && ctr.Amount.Cmp(currency.MinTransfer) < 0 {
+			ve.add(index, "amount", `parameter "amount" is less than min transfer %s %s`, currency.MinTransfer, currency.Code)
+		} else if !currency.MaxTransfer.IsZero() && ctr.Amount.Cmp(currency.MaxTransfer) > 0 {
+			ve.add(index, "amount", `parameter "amount" is greater than max transfer %s %s`, currency.MaxTransfer, currency.Code)
+		}
+	}
+}
+
+//normalize method
+func (ctr *CreateTransferRequestParams) normalize() {
+	ctr.To = ctr.To.Normalize()
+	ctr.Currency = ctr.Currency.Normalize()
+}
+
+//normalizeTransfers Normalized copies of transfers, the caller's params are not changed
+func normalizeTransfers(transfers []*CreateTransferRequestParams) []*CreateTransferRequestParams {
+	result := make([]*CreateTransferRequestParams, len(transfers))
+	for i, transfer := range transfers {
+		normalized := *transfer
+		normalized.normalize()
+		result[i] = &normalized
 	}
-	return err
+	return result
 }
 
 //CreateTransferRequest struct
@@ -38,31 +93,39 @@ type CreateTransferRequest struct {
 	Transfers []*CreateTransferRequestParams `xml:"transfer" json:"transfers"`
 }
 
+//getItemsCount method
+func (r *CreateTransferRequest) getItemsCount() int {
+	return len(r.Transfers)
+}
+
 //CreateTransferResponse struct
 type CreateTransferResponse struct {
 	ResponseBody
-	Transfers []*CreateTransferResponseParams `xml:"transfer,omitempty" json:"transfers,omitempty"`
+	Transfers        []*CreateTransferResponseParams `xml:"transfer,omitempty" json:"transfers,omitempty"`
+	Results          []*TransferResult               `xml:"-" json:"results,omitempty"`           //outcome of every submitted transfer in submission order
+	Simulated        bool                            `xml:"-" json:"simulated,omitempty"`         //transfers are not sent (dry-run)
+	SimulatedRequest string                          `xml:"-" json:"simulated_request,omitempty"` //request document of dry-run with masked credentials
 }
 
 //CreateTransferResponseParams struct
 type CreateTransferResponseParams struct {
-	Mode        string  `xml:"mode,attr" json:"mode"`
-	Code        uint64  `xml:"code,attr" json:"code"`
-	BatchNumber string  `xml:"batchnumber" json:"batchnumber"`
-	Date        string  `xml:"date" json:"date"`
-	Time        string  `xml:"time" json:"time"`
-	From        string  `xml:"from" json:"from"`
-	To          string  `xml:"to" json:"to"`
-	Fee         float64 `xml:"fee" json:"fee"`
-	Amount      float64 `xml:"amount" json:"amount"`
-	Total       float64 `xml:"total" json:"total"`
-	FeeMode     string  `xml:"fee_mode" json:"fee_mode"`
-	Currency    string  `xml:"currency" json:"currency"`
-	Note        string  `xml:"note" json:"note"`
-	Status      string  `xml:"status" json:"status"`
-	Type        string  `xml:"type" json:"type"`
-	Balance     float64 `xml:"balance" json:"balance"`
-	Method      string  `xml:"method" json:"method"`
+	Mode        string            `xml:"mode,attr" json:"mode"`
+	Code        uint64            `xml:"code,attr" json:"code"`
+	BatchNumber string            `xml:"batchnumber" json:"batchnumber"`
+	Date        string            `xml:"date" json:"date"`
+	Time        string            `xml:"time" json:"time"`
+	From        AccountNumber     `xml:"from" json:"from"`
+	To          AccountNumber     `xml:"to" json:"to"`
+	Fee         Amount            `xml:"fee" json:"fee"`
+	Amount      Amount            `xml:"amount" json:"amount"`
+	Total       Amount            `xml:"total" json:"total"`
+	FeeMode     string            `xml:"fee_mode" json:"fee_mode"`
+	Currency    string            `xml:"currency" json:"currency"`
+	Note        string            `xml:"note" json:"note"`
+	Status      TransactionStatus `xml:"status" json:"status"`
+	Type        string            `xml:"type" json:"type"`
+	Balance     Amount            `xml:"balance" json:"balance"`
+	Method      string            `xml:"method" json:"method"`
 }
 
 //GetHistoryRequest struct
@@ -71,13 +134,18 @@ type GetHistoryRequest struct {
 	History *GetHistoryRequestParams `xml:"history" json:"history"`
 }
 
+//getItemsCount method
+func (r *GetHistoryRequest) getItemsCount() int {
+	return 1
+}
+
 //GetHistoryRequestParams struct
 type GetHistoryRequestParams struct {
 	StartDate string          `xml:"start_date,omitempty" json:"start_date"` //for specify start date. format : YYYY-mm-dd example : 2011-03-01
 	EndDate   string          `xml:"end_date,omitempty" json:"end_date"`     //for specify end date. format : YYYY-mm-dd example : 2011-03-01
 	Type      TransactionType `xml:"type,omitempty" json:"type"`             //for specify transaction type. (transfer|topup|redeem|exchange|receive)
-	OrderBy   string          `xml:"order_by,omitempty" json:"order_by"`     //for specify order/sort by specific parameters (sorting) (date|amount|to|from|currency|bank)
-	Order     string          `xml:"order,omitempty" json:"order"`           //specify order type (ASC|DESC)
+	OrderBy   HistoryOrderBy  `xml:"order_by,omitempty" json:"order_by"`     //for specify order/sort by specific parameters (sorting) (date|amount|to|from|currency|bank)
+	Order     SortOrder       `xml:"order,omitempty" json:"order"`           //specify order type (ASC|DESC)
 	Page      uint64          `xml:"page,omitempty" json:"page"`             //for getting specific page from history transaction which has more than one page
 	PageSize  uint64          `xml:"page_size,omitempty" json:"page_size"`   //for specify how much transaction per page (max 20)
 }
@@ -103,17 +171,17 @@ type GetHistoryResponsePageParams struct {
 
 //GetHistoryResponseDetailParams struct
 type GetHistoryResponseDetailParams struct {
-	XMLName     xml.Name `xml:"detail" json:"-"`
-	BatchNumber string   `xml:"batchnumber" json:"batchnumber"`
-	Datetime    string   `xml:"datetime" json:"datetime"`
-	Type        string   `xml:"type" json:"type"`
-	To          string   `xml:"to" json:"to"`
-	From        string   `xml:"from" json:"from"`
-	Amount      float64  `xml:"amount" json:"amount"`
-	Note        string   `xml:"note" json:"note"`
-	Status      string   `xml:"status" json:"status"`
-	Currency    string   `xml:"currency" json:"currency"`
-	Fee         float64  `xml:"fee" json:"fee"`
+	XMLName     xml.Name          `xml:"detail" json:"-"`
+	BatchNumber string            `xml:"batchnumber" json:"batchnumber"`
+	Datetime    string            `xml:"datetime" json:"datetime"`
+	Type        string            `xml:"type" json:"type"`
+	To          AccountNumber     `xml:"to" json:"to"`
+	From        AccountNumber     `xml:"from" json:"from"`
+	Amount      Amount            `xml:"amount" json:"amount"`
+	Note        string            `xml:"note" json:"note"`
+	Status      TransactionStatus `xml:"status" json:"status"`
+	Currency    string            `xml:"currency" json:"currency"`
+	Fee         Amount            `xml:"fee" json:"fee"`
 }
 
 //GetDetailsRequest struct
@@ -122,6 +190,11 @@ type GetDetailsRequest struct {
 	Details []GetDetailsDetailParamsInterface `xml:"detail" json:"details"`
 }
 
+//getItemsCount method
+func (r *GetDetailsRequest) getItemsCount() int {
+	return len(r.Details)
+}
+
 //GetDetailsDetailParamsInterface interface
 type GetDetailsDetailParamsInterface interface {
 	GetDetailType() string
@@ -155,28 +228,30 @@ type GetDetailsResponse struct {
 
 //GetDetailsResponseDetailParams struct
 type GetDetailsResponseDetailParams struct {
-	XMLName     xml.Name `xml:"detail" json:"-"`
-	Mode        string   `xml:"mode,attr" json:"mode"`
-	Code        uint64   `xml:"code,attr" json:"code"`
-	BatchNumber string   `xml:"batchnumber" json:"batchnumber"`
-	Date        string   `xml:"date" json:"date"`
-	Time        string   `xml:"time" json:"time"`
-	From        string   `xml:"from" json:"from"`
-	To          string   `xml:"to" json:"to"`
-	Amount      float64  `xml:"amount" json:"amount"`
-	Total       float64  `xml:"total" json:"total"`
-	Currency    string   `xml:"currency" json:"currency"`
-	Note        string   `xml:"note" json:"note"`
-	Status      string   `xml:"status" json:"status"`
-	Fee         float64  `xml:"fee" json:"fee"`
-	Type        string   `xml:"type" json:"type"`
-	Method      string   `xml:"method" json:"method"`
-	FeeMode     string   `xml:"fee_mod" json:"fee_mod"`
+	XMLName     xml.Name          `xml:"detail" json:"-"`
+	Mode        string            `xml:"mode,attr" json:"mode"`
+	Code        uint64            `xml:"code,attr" json:"code"`
+	BatchNumber string            `xml:"batchnumber" json:"batchnumber"`
+	Date        string            `xml:"date" json:"date"`
+	Time        string            `xml:"time" json:"time"`
+	From        AccountNumber     `xml:"from" json:"from"`
+	To          AccountNumber     `xml:"to" json:"to"`
+	Amount      Amount            `xml:"amount" json:"amount"`
+	Total       Amount            `xml:"total" json:"total"`
+	Currency    string            `xml:"currency" json:"currency"`
+	Note        string            `xml:"note" json:"note"`
+	Status      TransactionStatus `xml:"status" json:"status"`
+	Fee         Amount            `xml:"fee" json:"fee"`
+	Type        string            `xml:"type" json:"type"`
+	Method      string            `xml:"method" json:"method"`
+	FeeMode     string            `xml:"fee_mod" json:"fee_mod"`
 }
 
 //TransfersResource struct
 type TransfersResource struct {
 	ResourceAbstract
+	store  TransferStore
+	dryRun bool
 }
 
 //CreateTransfer method - allow you to transfer fund from one account to another.
@@ -225,28 +300,47 @@ type TransfersResource struct {
 //    </transfer>
 //</fasa_request>
 //
-func (r *TransfersResource) CreateTransfer(transfers []*CreateTransferRequestParams, ctx context.Context, attributes *RequestParamsAttributes) (*CreateTransferResponse, *http.Response, error) {
+func (r *TransfersResource) CreateTransfer(params []*CreateTransferRequestParams, ctx context.Context, attributes *RequestParamsAttributes) (*CreateTransferResponse, *http.Response, error) {
+	transfers := normalizeTransfers(params)
 	err := r.validateTransferParams(transfers)
 	if err != nil {
-		return nil, nil, fmt.Errorf("TransfersResource.CreateTransfer error: %v", err)
+		return nil, nil, fmt.Errorf("TransfersResource.CreateTransfer error: %w", err)
 	}
-	baseRequestParams := r.buildRequestParams(attributes)
-	requestParams := &CreateTransferRequest{baseRequestParams, transfers}
-	bytesRequest, err := r.marshalRequestParams(requestParams)
-	if err != nil {
-		return nil, nil, fmt.Errorf("TransfersResource.CreateTransfer error: %v", err)
+	if r.dryRun || IsDryRun(ctx) {
+		if r.store != nil {
+			//reference codes are assigned to the copies only, so the simulated document matches the real one
+			if err := assignTransferRefs(transfers); err != nil {
+				return nil, nil, fmt.Errorf("TransfersResource.CreateTransfer error: %w", err)
+			}
+		}
+		result, err := r.simulateCreateTransfer(transfers, attributes)
+		if err != nil {
+			return nil, nil, fmt.Errorf("TransfersResource.CreateTransfer error: %w", err)
+		}
+		return result, nil, nil
 	}
-	rsp, err := r.tr.SendRequest(ctx, bytesRequest)
-	if err != nil {
-		return nil, nil, fmt.Errorf("TransfersResource.CreateTransfer error: %v", err)
+	if r.store != nil {
+		//assigned reference codes are kept in the caller's params to recognize retries
+		if err := assignTransferRefs(params); err != nil {
+			return nil, nil, fmt.Errorf("TransfersResource.CreateTransfer error: %w", err)
+		}
+		result, rsp, err := r.createTransferIdempotent(normalizeTransfers(params), ctx, attributes)
+		var apiErr *APIError
+		if err != nil && !errors.As(err, &apiErr) {
+			return result, rsp, fmt.Errorf("TransfersResource.CreateTransfer error: %w", err)
+		}
+		return result, rsp, err
 	}
+	baseRequestParams := r.buildRequestParams(attributes)
+	requestParams := &CreateTransferRequest{baseRequestParams, transfers}
 	var result CreateTransferResponse
-	err = r.unmarshalResponse(rsp, &result)
+	rsp, err := r.execute(ctx, OperationCreateTransfer, requestParams, &result)
 	if err != nil {
-		return nil, rsp, fmt.Errorf("TransfersResource.CreateTransfer error: %v", err)
+		return nil, rsp, fmt.Errorf("TransfersResource.CreateTransfer error: %w", err)
 	}
+	result.Results = correlateTransferResults(transfers, &result)
 	if !result.IsSuccess() {
-		return &result, rsp, fmt.Errorf(result.GetError())
+		return &result, rsp, result.GetAPIError()
 	}
 	return &result, rsp, nil
 }
@@ -273,23 +367,20 @@ func (r *TransfersResource) CreateTransfer(transfers []*CreateTransferRequestPar
 //</fasa_request>
 //
 func (r *TransfersResource) GetHistory(history *GetHistoryRequestParams, ctx context.Context, attributes *RequestParamsAttributes) (*GetHistoryResponse, *http.Response, error) {
+	if history != nil {
+		if err := history.isValid(); err != nil {
+			return nil, nil, fmt.Errorf("TransfersResource.GetHistory error: %w", err)
+		}
+	}
 	baseRequestParams := r.buildRequestParams(attributes)
 	requestParams := &GetHistoryRequest{baseRequestParams, history}
-	bytesRequest, err := r.marshalRequestParams(requestParams)
-	if err != nil {
-		return nil, nil, fmt.Errorf("TransfersResource.GetHistory error: %v", err)
-	}
-	rsp, err := r.tr.SendRequest(ctx, bytesRequest)
-	if err != nil {
-		return nil, nil, fmt.Errorf("TransfersResource.GetHistory error: %v", err)
-	}
 	var result GetHistoryResponse
-	err = r.unmarshalResponse(rsp, &result)
+	rsp, err := r.execute(ctx, OperationGetHistory, requestParams, &result)
 	if err != nil {
-		return nil, rsp, fmt.Errorf("TransfersResource.GetHistory error: %v", err)
+		return nil, rsp, fmt.Errorf("TransfersResource.GetHistory error: %w", err)
 	}
 	if !result.IsSuccess() {
-		return &result, rsp, fmt.Errorf(result.GetError())
+		return &result, rsp, result.GetAPIError()
 	}
 	return &result, rsp, nil
 }
@@ -333,33 +424,22 @@ func (r *TransfersResource) GetHistory(history *GetHistoryRequestParams, ctx con
 func (r *TransfersResource) GetDetails(details []GetDetailsDetailParamsInterface, ctx context.Context, attributes *RequestParamsAttributes) (*GetDetailsResponse, *http.Response, error) {
 	baseRequestParams := r.buildRequestParams(attributes)
 	requestParams := &GetDetailsRequest{baseRequestParams, details}
-	bytesRequest, err := r.marshalRequestParams(requestParams)
-	if err != nil {
-		return nil, nil, fmt.Errorf("TransfersResource.GetDetails error: %v", err)
-	}
-	rsp, err := r.tr.SendRequest(ctx, bytesRequest)
-	if err != nil {
-		return nil, nil, fmt.Errorf("TransfersResource.GetDetails error: %v", err)
-	}
 	var result GetDetailsResponse
-	err = r.unmarshalResponse(rsp, &result)
+	rsp, err := r.execute(ctx, OperationGetDetails, requestParams, &result)
 	if err != nil {
-		return nil, rsp, fmt.Errorf("TransfersResource.GetDetails error: %v", err)
+		return nil, rsp, fmt.Errorf("TransfersResource.GetDetails error: %w", err)
 	}
 	if !result.IsSuccess() {
-		return &result, rsp, fmt.Errorf(result.GetError())
+		return &result, rsp, result.GetAPIError()
 	}
 	return &result, rsp, nil
 }
 
-//validateTransferParams method
+//validateTransferParams method - collect violations of all transfers before any network call
 func (r *TransfersResource) validateTransferParams(transfers []*CreateTransferRequestParams) error {
-	var err error
-	for _, transfer := range transfers {
-		err = transfer.isValid()
-		if err != nil {
-			break
-		}
+	ve := &ValidationError{}
+	for index, transfer := range transfers {
+		transfer.validate(index, ve)
 	}
-	return err
+	return ve.errorOrNil()
 }
@@ -4,12 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"encoding/xml"
+	"errors"
 	"github.com/jarcoal/httpmock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"io/ioutil"
 	"net/http"
 	"testing"
+	"time"
 )
 
 type TransfersTestSuite struct {
@@ -38,7 +40,7 @@ func (suite *TransfersTestSuite) TestGetHistoryResponseMarshalJsonSuccess() {
 	err := xml.Unmarshal(body, &response)
 
 	assert.NoError(suite.T(), err)
-	expected := `{"id":"1312342474","date_time":"2011-08-03T10:34:34+07:00","history":{"page":{"total_item":579,"page_count":58,"current_page":0},"details":[{"batchnumber":"TR2011072685119","datetime":"2011-07-26 15:44:35","type":"Keluar","to":"FP10500","from":"FP12049","amount":11160,"note":"Pembayaran untuk pembelian Liberty Reserve","status":"FINISH","currency":"","fee":0},{"batchnumber":"TR2011072521135","datetime":"2011-07-25 11:38:43","type":"Keluar","to":"FP89680","from":"FP12049","amount":1000,"note":"standart operation","status":"FINISH","currency":"","fee":0}]}}`
+	expected := `{"id":"1312342474","date_time":"2011-08-03T10:34:34+07:00","history":{"page":{"total_item":579,"page_count":58,"current_page":0},"details":[{"batchnumber":"TR2011072685119","datetime":"2011-07-26 15:44:35","type":"Keluar","to":"FP10500","from":"FP12049","amount":11160.000,"note":"Pembayaran untuk pembelian Liberty Reserve","status":"FINISH","currency":"","fee":0},{"batchnumber":"TR2011072521135","datetime":"2011-07-25 11:38:43","type":"Keluar","to":"FP89680","from":"FP12049","amount":1000.000,"note":"standart operation","status":"FINISH","currency":"","fee":0}]}}`
 	bytes, err := json.Marshal(&response)
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), expected, string(bytes))
@@ -62,7 +64,7 @@ func (suite *TransfersTestSuite) TestGetDetailsResponseMarshalJsonSuccess() {
 	err := xml.Unmarshal(body, &response)
 
 	assert.NoError(suite.T(), err)
-	expected := `{"id":"1234567","date_time":"2013-01-01T10:58:43+07:00","details":[{"mode":"detail","code":210,"batchnumber":"TR2012092791234","date":"2012-10-20","time":"10:09:36","from":"FP00001","to":"FP00002","amount":1000,"total":1100,"currency":"IDR","note":"Payment for something","status":"FINISH","fee":100,"type":"Transfer Out","method":"api_xml","fee_mod":"FiS"}]}`
+	expected := `{"id":"1234567","date_time":"2013-01-01T10:58:43+07:00","details":[{"mode":"detail","code":210,"batchnumber":"TR2012092791234","date":"2012-10-20","time":"10:09:36","from":"FP00001","to":"FP00002","amount":1000.000,"total":1100,"currency":"IDR","note":"Payment for something","status":"FINISH","fee":100.000,"type":"Transfer Out","method":"api_xml","fee_mod":"FiS"}]}`
 	bytes, err := json.Marshal(&response)
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), expected, string(bytes))
@@ -84,7 +86,7 @@ func (suite *TransfersTestSuite) TestCreateTransferRequestMarshalXmlSuccess() {
 	transfer := &CreateTransferRequestParams{
 		Id:       "123",
 		To:       "FP89680",
-		Amount:   1000.0,
+		Amount:   MustParseAmount("1000"),
 		Currency: CurrencyCodeIDR,
 		Note:     "standart operation",
 	}
@@ -99,7 +101,7 @@ func (suite *TransfersTestSuite) TestCreateTransferRequestIsValidSuccess() {
 	transfer := &CreateTransferRequestParams{
 		Id:       "123",
 		To:       "FP89680",
-		Amount:   1000.0,
+		Amount:   MustParseAmount("1000"),
 		Currency: CurrencyCodeIDR,
 		Note:     "standart operation",
 	}
@@ -110,7 +112,7 @@ func (suite *TransfersTestSuite) TestCreateTransferRequestIsValidSuccess() {
 func (suite *TransfersTestSuite) TestCreateTransferRequestIsValidEmptyParameterTo() {
 	transfer := &CreateTransferRequestParams{
 		Id:       "123",
-		Amount:   1000.0,
+		Amount:   MustParseAmount("1000"),
 		Currency: CurrencyCodeIDR,
 		Note:     "standart operation",
 	}
@@ -123,7 +125,7 @@ func (suite *TransfersTestSuite) TestCreateTransferRequestIsValidEmptyParameterC
 	transfer := &CreateTransferRequestParams{
 		Id:     "123",
 		To:     "FP89680",
-		Amount: 1000.0,
+		Amount: MustParseAmount("1000"),
 		Note:   "standart operation",
 	}
 	result := transfer.isValid()
@@ -143,13 +145,31 @@ func (suite *TransfersTestSuite) TestCreateTransferRequestIsValidEmptyParameterA
 	assert.Equal(suite.T(), `parameter "amount" is empty`, result.Error())
 }
 
+func (suite *TransfersTestSuite) TestCreateTransferRequestIsValidUnsupportedCurrency() {
+	transfer := &CreateTransferRequestParams{
+		Id:       "123",
+		To:       "FP89680",
+		Amount:   MustParseAmount("1000"),
+		Currency: "CHY",
+	}
+	result := transfer.isValid()
+	assert.Error(suite.T(), result)
+	assert.Equal(suite.T(), `parameter "currency" is not supported`, result.Error())
+}
+
+func (suite *TransfersTestSuite) TestCreateTransferRequestNormalize() {
+	transfer := &CreateTransferRequestParams{Currency: " idr"}
+	transfer.normalize()
+	assert.Equal(suite.T(), CurrencyCodeIDR, transfer.Currency)
+}
+
 func (suite *TransfersTestSuite) TestCreateTransferResponseMarshalJsonSuccess() {
 	var response CreateTransferResponse
 	body, _ := LoadStubResponseData("stubs/transfers/transfer/success.xml")
 	err := xml.Unmarshal(body, &response)
 
 	assert.NoError(suite.T(), err)
-	expected := `{"id":"1311059195","date_time":"2011-07-19T14:06:35+07:00","transfers":[{"mode":"transfer","code":203,"batchnumber":"TR2011071917277","date":"2011-07-19","time":"14:06:35","from":"FP12049","to":"FP89680","fee":100,"amount":1000,"total":1100,"fee_mode":"FiS","currency":"IDR","note":"standart operation","status":"FINISH","type":"Keluar","balance":2815832,"method":"xml_api"}]}`
+	expected := `{"id":"1311059195","date_time":"2011-07-19T14:06:35+07:00","transfers":[{"mode":"transfer","code":203,"batchnumber":"TR2011071917277","date":"2011-07-19","time":"14:06:35","from":"FP12049","to":"FP89680","fee":100,"amount":1000.0,"total":1100.0,"fee_mode":"FiS","currency":"IDR","note":"standart operation","status":"FINISH","type":"Keluar","balance":2815832.00,"method":"xml_api"}]}`
 	bytes, err := json.Marshal(&response)
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), expected, string(bytes))
@@ -183,7 +203,7 @@ func (suite *TransfersResourceTestSuite) SetupTest() {
 	transport := BuildStubHttpTransport()
 	suite.cfg = cfg
 	suite.ctx = context.Background()
-	suite.testable = &TransfersResource{NewResourceAbstract(transport, cfg)}
+	suite.testable = &TransfersResource{ResourceAbstract: NewResourceAbstract(transport, cfg)}
 	httpmock.Activate()
 }
 
@@ -212,20 +232,20 @@ func (suite *TransfersResourceTestSuite) TestGetHistorySuccess() {
 	assert.Equal(suite.T(), "TR2011072685119", result.History.Details[0].BatchNumber)
 	assert.Equal(suite.T(), "2011-07-26 15:44:35", result.History.Details[0].Datetime)
 	assert.Equal(suite.T(), "Keluar", result.History.Details[0].Type)
-	assert.Equal(suite.T(), "FP10500", result.History.Details[0].To)
-	assert.Equal(suite.T(), "FP12049", result.History.Details[0].From)
-	assert.Equal(suite.T(), 11160.000, result.History.Details[0].Amount)
+	assert.Equal(suite.T(), AccountNumber("FP10500"), result.History.Details[0].To)
+	assert.Equal(suite.T(), AccountNumber("FP12049"), result.History.Details[0].From)
+	assert.Equal(suite.T(), MustParseAmount("11160.000"), result.History.Details[0].Amount)
 	assert.Equal(suite.T(), "Pembayaran untuk pembelian Liberty Reserve", result.History.Details[0].Note)
-	assert.Equal(suite.T(), "FINISH", result.History.Details[0].Status)
+	assert.Equal(suite.T(), TransactionStatusFinish, result.History.Details[0].Status)
 
 	assert.Equal(suite.T(), "TR2011072521135", result.History.Details[1].BatchNumber)
 	assert.Equal(suite.T(), "2011-07-25 11:38:43", result.History.Details[1].Datetime)
 	assert.Equal(suite.T(), "Keluar", result.History.Details[1].Type)
-	assert.Equal(suite.T(), "FP89680", result.History.Details[1].To)
-	assert.Equal(suite.T(), "FP12049", result.History.Details[1].From)
-	assert.Equal(suite.T(), 1000.000, result.History.Details[1].Amount)
+	assert.Equal(suite.T(), AccountNumber("FP89680"), result.History.Details[1].To)
+	assert.Equal(suite.T(), AccountNumber("FP12049"), result.History.Details[1].From)
+	assert.Equal(suite.T(), MustParseAmount("1000.000"), result.History.Details[1].Amount)
 	assert.Equal(suite.T(), "standart operation", result.History.Details[1].Note)
-	assert.Equal(suite.T(), "FINISH", result.History.Details[1].Status)
+	assert.Equal(suite.T(), TransactionStatusFinish, result.History.Details[1].Status)
 	//response
 	defer resp.Body.Close()
 	bodyRsp, _ := ioutil.ReadAll(resp.Body)
@@ -259,7 +279,7 @@ func (suite *TransfersResourceTestSuite) TestGetHistoryXmlError() {
 	bodyRsp, _ := ioutil.ReadAll(resp.Body)
 	assert.Equal(suite.T(), body, bodyRsp)
 	//error
-	assert.Equal(suite.T(), "UNEXPECTED ERROR", err.Error())
+	assert.Equal(suite.T(), ErrorMessageTransactionNotFound, err.Error())
 }
 
 func (suite *TransfersResourceTestSuite) TestGetHistoryNonXmlError() {
@@ -276,7 +296,38 @@ func (suite *TransfersResourceTestSuite) TestGetHistoryNonXmlError() {
 	bodyRsp, _ := ioutil.ReadAll(resp.Body)
 	assert.Equal(suite.T(), body, bodyRsp)
 	//error
-	assert.Equal(suite.T(), "TransfersResource.GetHistory error: EOF", err.Error())
+	assert.Equal(suite.T(), `TransfersResource.GetHistory error: unexpected HTTP response: status 200, content type "", body "Bad request"`, err.Error())
+}
+
+func (suite *TransfersResourceTestSuite) TestGetHistoryHttpStatusError() {
+	body, _ := LoadStubResponseData("stubs/errors/500.html")
+	httpmock.RegisterResponder(http.MethodPost, suite.cfg.Uri, func(req *http.Request) (*http.Response, error) {
+		rsp := httpmock.NewBytesResponse(http.StatusInternalServerError, body)
+		rsp.Header.Set("Content-Type", "text/html")
+		return rsp, nil
+	})
+
+	historyFilter := &GetHistoryRequestParams{StartDate: "2022-03-01", EndDate: "2022-03-28"}
+	result, resp, err := suite.testable.GetHistory(historyFilter, suite.ctx, nil)
+	assert.Error(suite.T(), err)
+	assert.NotEmpty(suite.T(), resp)
+	assert.Empty(suite.T(), result)
+	//error
+	var httpErr *HTTPError
+	assert.True(suite.T(), errors.As(err, &httpErr))
+	assert.Equal(suite.T(), http.StatusInternalServerError, httpErr.StatusCode)
+	assert.Equal(suite.T(), "text/html", httpErr.ContentType)
+	assert.Equal(suite.T(), "Bad request", httpErr.Body)
+}
+
+func (suite *TransfersResourceTestSuite) TestGetHistoryInvalidParams() {
+	historyFilter := &GetHistoryRequestParams{StartDate: "2022-03-28", EndDate: "2022-03-01"}
+	result, resp, err := suite.testable.GetHistory(historyFilter, suite.ctx, nil)
+	assert.Error(suite.T(), err)
+	assert.Nil(suite.T(), resp)
+	assert.Nil(suite.T(), result)
+	assert.Equal(suite.T(), `TransfersResource.GetHistory error: parameter "end_date" is before "start_date"`, err.Error())
+	assert.Equal(suite.T(), 0, httpmock.GetTotalCallCount())
 }
 
 func (suite *TransfersResourceTestSuite) TestGetDetailsSuccess() {
@@ -299,15 +350,15 @@ func (suite *TransfersResourceTestSuite) TestGetDetailsSuccess() {
 	assert.Equal(suite.T(), "TR2012092791234", result.Details[0].BatchNumber)
 	assert.Equal(suite.T(), "2012-10-20", result.Details[0].Date)
 	assert.Equal(suite.T(), "10:09:36", result.Details[0].Time)
-	assert.Equal(suite.T(), "FP00001", result.Details[0].From)
-	assert.Equal(suite.T(), "FP00002", result.Details[0].To)
-	assert.Equal(suite.T(), 1000.000, result.Details[0].Amount)
-	assert.Equal(suite.T(), 100.000, result.Details[0].Fee)
-	assert.Equal(suite.T(), float64(1100), result.Details[0].Total)
+	assert.Equal(suite.T(), AccountNumber("FP00001"), result.Details[0].From)
+	assert.Equal(suite.T(), AccountNumber("FP00002"), result.Details[0].To)
+	assert.Equal(suite.T(), MustParseAmount("1000.000"), result.Details[0].Amount)
+	assert.Equal(suite.T(), MustParseAmount("100.000"), result.Details[0].Fee)
+	assert.Equal(suite.T(), MustParseAmount("1100"), result.Details[0].Total)
 	assert.Equal(suite.T(), "FiS", result.Details[0].FeeMode)
 	assert.Equal(suite.T(), "IDR", result.Details[0].Currency)
 	assert.Equal(suite.T(), "Payment for something", result.Details[0].Note)
-	assert.Equal(suite.T(), "FINISH", result.Details[0].Status)
+	assert.Equal(suite.T(), TransactionStatusFinish, result.Details[0].Status)
 	assert.Equal(suite.T(), "Transfer Out", result.Details[0].Type)
 	assert.Equal(suite.T(), "api_xml", result.Details[0].Method)
 	//response
@@ -345,7 +396,7 @@ func (suite *TransfersResourceTestSuite) TestGetDetailsXmlError() {
 	bodyRsp, _ := ioutil.ReadAll(resp.Body)
 	assert.Equal(suite.T(), body, bodyRsp)
 	//error
-	assert.Equal(suite.T(), "UNEXPECTED ERROR", err.Error())
+	assert.Equal(suite.T(), ErrorMessageTransactionNotFound, err.Error())
 }
 
 func (suite *TransfersResourceTestSuite) TestGetDetailsNonXmlError() {
@@ -364,7 +415,7 @@ func (suite *TransfersResourceTestSuite) TestGetDetailsNonXmlError() {
 	bodyRsp, _ := ioutil.ReadAll(resp.Body)
 	assert.Equal(suite.T(), body, bodyRsp)
 	//error
-	assert.Equal(suite.T(), "TransfersResource.GetDetails error: EOF", err.Error())
+	assert.Equal(suite.T(), `TransfersResource.GetDetails error: unexpected HTTP response: status 200, content type "", body "Bad request"`, err.Error())
 }
 
 func (suite *TransfersResourceTestSuite) TestCreateTransferSuccess() {
@@ -374,7 +425,7 @@ func (suite *TransfersResourceTestSuite) TestCreateTransferSuccess() {
 	transfer := &CreateTransferRequestParams{
 		Id:       "123",
 		To:       "FP89680",
-		Amount:   1000.0,
+		Amount:   MustParseAmount("1000"),
 		Currency: CurrencyCodeIDR,
 		Note:     "standart operation",
 	}
@@ -392,17 +443,17 @@ func (suite *TransfersResourceTestSuite) TestCreateTransferSuccess() {
 	assert.Equal(suite.T(), "TR2011071917277", result.Transfers[0].BatchNumber)
 	assert.Equal(suite.T(), "2011-07-19", result.Transfers[0].Date)
 	assert.Equal(suite.T(), "14:06:35", result.Transfers[0].Time)
-	assert.Equal(suite.T(), "FP12049", result.Transfers[0].From)
-	assert.Equal(suite.T(), "FP89680", result.Transfers[0].To)
-	assert.Equal(suite.T(), 1000.0, result.Transfers[0].Amount)
-	assert.Equal(suite.T(), float64(100), result.Transfers[0].Fee)
-	assert.Equal(suite.T(), 1100.0, result.Transfers[0].Total)
+	assert.Equal(suite.T(), AccountNumber("FP12049"), result.Transfers[0].From)
+	assert.Equal(suite.T(), AccountNumber("FP89680"), result.Transfers[0].To)
+	assert.Equal(suite.T(), MustParseAmount("1000.0"), result.Transfers[0].Amount)
+	assert.Equal(suite.T(), MustParseAmount("100"), result.Transfers[0].Fee)
+	assert.Equal(suite.T(), MustParseAmount("1100.0"), result.Transfers[0].Total)
 	assert.Equal(suite.T(), "FiS", result.Transfers[0].FeeMode)
 	assert.Equal(suite.T(), "IDR", result.Transfers[0].Currency)
 	assert.Equal(suite.T(), "standart operation", result.Transfers[0].Note)
-	assert.Equal(suite.T(), "FINISH", result.Transfers[0].Status)
+	assert.Equal(suite.T(), TransactionStatusFinish, result.Transfers[0].Status)
 	assert.Equal(suite.T(), "Keluar", result.Transfers[0].Type)
-	assert.Equal(suite.T(), 2815832.00, result.Transfers[0].Balance)
+	assert.Equal(suite.T(), MustParseAmount("2815832.00"), result.Transfers[0].Balance)
 	assert.Equal(suite.T(), "xml_api", result.Transfers[0].Method)
 	//response
 	defer resp.Body.Close()
@@ -410,10 +461,30 @@ func (suite *TransfersResourceTestSuite) TestCreateTransferSuccess() {
 	assert.Equal(suite.T(), body, bodyRsp)
 }
 
+func (suite *TransfersResourceTestSuite) TestCreateTransferIsNotRetried() {
+	calls := 0
+	httpmock.RegisterResponder(http.MethodPost, suite.cfg.Uri, func(req *http.Request) (*http.Response, error) {
+		calls++
+		return httpmock.NewStringResponse(http.StatusServiceUnavailable, "Service unavailable"), nil
+	})
+	suite.testable.tr.retry = &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}
+
+	transfer := &CreateTransferRequestParams{
+		Id:       "123",
+		To:       "FP89680",
+		Amount:   MustParseAmount("1000"),
+		Currency: CurrencyCodeIDR,
+		Note:     "standart operation",
+	}
+	_, _, err := suite.testable.CreateTransfer([]*CreateTransferRequestParams{transfer}, suite.ctx, nil)
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), 1, calls)
+}
+
 func (suite *TransfersResourceTestSuite) TestCreateTransferRequestError() {
 	transfer := &CreateTransferRequestParams{
 		Id:       "123",
-		Amount:   1000.0,
+		Amount:   MustParseAmount("1000"),
 		Currency: CurrencyCodeIDR,
 		Note:     "standart operation",
 	}
@@ -433,7 +504,7 @@ func (suite *TransfersResourceTestSuite) TestCreateTransferXmlError() {
 	transfer := &CreateTransferRequestParams{
 		Id:       "123",
 		To:       "FP89680",
-		Amount:   1000.0,
+		Amount:   MustParseAmount("1000"),
 		Currency: CurrencyCodeIDR,
 		Note:     "standart operation",
 	}
@@ -471,6 +542,17 @@ func (suite *TransfersResourceTestSuite) TestCreateTransferXmlError() {
 	assert.Equal(suite.T(), body, bodyRsp)
 	//error
 	assert.Equal(suite.T(), "NOT ACCEPTABLE TRANSFER", err.Error())
+	var apiErr *APIError
+	assert.True(suite.T(), errors.As(err, &apiErr))
+	assert.Equal(suite.T(), "tid3", apiErr.Id)
+	assert.Equal(suite.T(), "transfer", apiErr.Mode)
+	assert.Equal(suite.T(), uint64(40600), apiErr.Code)
+	assert.Len(suite.T(), apiErr.Errors, 3)
+	assert.Equal(suite.T(), uint64(40602), apiErr.Errors[2].Code)
+	assert.True(suite.T(), errors.Is(err, ErrNotAcceptableTransfer))
+	assert.True(suite.T(), errors.Is(err, ErrUnknownRecipient))
+	assert.True(suite.T(), errors.Is(err, ErrAmountOverLimit))
+	assert.True(suite.T(), errors.Is(err, ErrEmptyCurrency))
 }
 
 func (suite *TransfersResourceTestSuite) TestCreateTransferNonXmlError() {
@@ -480,7 +562,7 @@ func (suite *TransfersResourceTestSuite) TestCreateTransferNonXmlError() {
 	transfer := &CreateTransferRequestParams{
 		Id:       "123",
 		To:       "FP89680",
-		Amount:   1000.0,
+		Amount:   MustParseAmount("1000"),
 		Currency: CurrencyCodeIDR,
 		Note:     "standart operation",
 	}
@@ -494,14 +576,14 @@ func (suite *TransfersResourceTestSuite) TestCreateTransferNonXmlError() {
 	bodyRsp, _ := ioutil.ReadAll(resp.Body)
 	assert.Equal(suite.T(), body, bodyRsp)
 	//error
-	assert.Equal(suite.T(), "TransfersResource.CreateTransfer error: EOF", err.Error())
+	assert.Equal(suite.T(), `TransfersResource.CreateTransfer error: unexpected HTTP response: status 200, content type "", body "Bad request"`, err.Error())
 }
 
 func (suite *TransfersResourceTestSuite) TestValidateTransferParamsValid() {
 	transfer := &CreateTransferRequestParams{
 		Id:       "123",
 		To:       "FP89680",
-		Amount:   1000.0,
+		Amount:   MustParseAmount("1000"),
 		Currency: CurrencyCodeIDR,
 		Note:     "standart operation",
 	}
@@ -514,13 +596,13 @@ func (suite *TransfersResourceTestSuite) TestValidateTransferParamsInvalid() {
 	transfer1 := &CreateTransferRequestParams{
 		Id:       "123",
 		To:       "FP89680",
-		Amount:   1000.0,
+		Amount:   MustParseAmount("1000"),
 		Currency: CurrencyCodeIDR,
 		Note:     "standart operation",
 	}
 	transfer2 := &CreateTransferRequestParams{
 		Id:       "123",
-		Amount:   1000.0,
+		Amount:   MustParseAmount("1000"),
 		Currency: CurrencyCodeIDR,
 		Note:     "standart operation",
 	}
@@ -529,6 +611,48 @@ func (suite *TransfersResourceTestSuite) TestValidateTransferParamsInvalid() {
 	assert.Equal(suite.T(), `parameter "to" is empty`, err.Error())
 }
 
+func (suite *TransfersResourceTestSuite) TestValidateTransferParamsCollectsAllViolations() {
+	transfer1 := &CreateTransferRequestParams{
+		To:       "FP89680",
+		Currency: CurrencyCodeIDR,
+	}
+	transfer2 := &CreateTransferRequestParams{
+		Amount:   MustParseAmount("1000"),
+		Currency: "CHY",
+	}
+	err := suite.testable.validateTransferParams([]*CreateTransferRequestParams{transfer1, transfer2})
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), `3 validation errors: transfer 0: parameter "amount" is empty; transfer 1: parameter "to" is empty; transfer 1: parameter "currency" is not supported`, err.Error())
+}
+
+func (suite *TransfersResourceTestSuite) TestCreateTransferResults() {
+	body, _ := LoadStubResponseData("stubs/transfers/transfer/error.xml")
+	httpmock.RegisterResponder(http.MethodPost, suite.cfg.Uri, httpmock.NewBytesResponder(http.StatusOK, body))
+
+	transfers := []*CreateTransferRequestParams{
+		{Id: "tid2", To: "FP89680", Amount: MustParseAmount("1000"), Currency: CurrencyCodeIDR},
+		{Id: "tid3", To: "FP89681", Amount: MustParseAmount("1000"), Currency: CurrencyCodeIDR},
+	}
+	result, _, err := suite.testable.CreateTransfer(transfers, suite.ctx, nil)
+	assert.Error(suite.T(), err)
+	assert.Len(suite.T(), result.Results, 2)
+	assert.Equal(suite.T(), TransferResultStatusNotProcessed, result.Results[0].Status)
+	assert.Equal(suite.T(), TransferResultStatusRejected, result.Results[1].Status)
+	assert.Equal(suite.T(), "tid3", result.Results[1].Id)
+}
+
+func (suite *TransfersResourceTestSuite) TestCreateTransferKeepsParams() {
+	body, _ := LoadStubResponseData("stubs/transfers/transfer/success.xml")
+	httpmock.RegisterResponder(http.MethodPost, suite.cfg.Uri, httpmock.NewBytesResponder(http.StatusOK, body))
+
+	transfer := &CreateTransferRequestParams{To: " fp89680", Amount: MustParseAmount("1000"), Currency: "idr"}
+	result, _, err := suite.testable.CreateTransfer([]*CreateTransferRequestParams{transfer}, suite.ctx, nil)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), TransferResultStatusAccepted, result.Results[0].Status)
+	assert.Equal(suite.T(), AccountNumber(" fp89680"), transfer.To)
+	assert.Equal(suite.T(), CurrencyCode("idr"), transfer.Currency)
+}
+
 func TestTransfersResourceTestSuite(t *testing.T) {
 	suite.Run(t, new(TransfersResourceTestSuite))
 }
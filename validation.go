@@ -0,0 +1,67 @@
+package fasapay
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	//TransferIdMaxLength max length of the transfer id
+	TransferIdMaxLength = 50
+	//TransferNoteMaxLength max length of the transfer note
+	TransferNoteMaxLength = 255
+	//TransferRefMaxLength max length of the transfer reference code
+	TransferRefMaxLength = 50
+)
+
+//FieldError struct - violation of the request parameter
+type FieldError struct {
+	Index   int    `json:"index"`   //index of the item in the batch request
+	Field   string `json:"field"`   //name of the parameter
+	Message string `json:"message"` //description of the violation
+}
+
+//Error method
+func (fe *FieldError) Error() string {
+	return fe.Message
+}
+
+//ValidationError struct - all violations of the request parameters
+type ValidationError struct {
+	Errors []*FieldError `json:"errors"`
+}
+
+//Error method
+func (ve *ValidationError) Error() string {
+	if len(ve.Errors) == 1 {
+		return ve.Errors[0].Error()
+	}
+	messages := make([]string, len(ve.Errors))
+	for i, fieldError := range ve.Errors {
+		messages[i] = fmt.Sprintf("transfer %d: %s", fieldError.Index, fieldError.Message)
+	}
+	return fmt.Sprintf("%d validation errors: %s", len(ve.Errors), strings.Join(messages, "; "))
+}
+
+//HasField method - is any violation of the parameter found
+func (ve *ValidationError) HasField(field string) bool {
+	for _, fieldError := range ve.Errors {
+		if fieldError.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+//add method
+func (ve *ValidationError) add(index int, field string, format string, args ...interface{}) {
+	ve.Errors = append(ve.Errors, &FieldError{Index: index, Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+//errorOrNil method
+func (ve *ValidationError) errorOrNil() error {
+	if len(ve.Errors) == 0 {
+		return nil
+	}
+	return ve
+}
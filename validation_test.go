@@ -0,0 +1,94 @@
+package fasapay
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"strings"
+	"testing"
+)
+
+type ValidationTestSuite struct {
+	suite.Suite
+}
+
+func (suite *ValidationTestSuite) TearDownTest() {
+	currencies.mu.Lock()
+	delete(currencies.items, "EUR")
+	currencies.mu.Unlock()
+}
+
+func (suite *ValidationTestSuite) TestValidationErrorSingle() {
+	ve := &ValidationError{}
+	ve.add(1, "to", `parameter "to" is empty`)
+	assert.Equal(suite.T(), `parameter "to" is empty`, ve.Error())
+	assert.True(suite.T(), ve.HasField("to"))
+	assert.False(suite.T(), ve.HasField("amount"))
+}
+
+func (suite *ValidationTestSuite) TestValidationErrorMultiple() {
+	ve := &ValidationError{}
+	ve.add(0, "to", `parameter "to" is empty`)
+	ve.add(2, "amount", `parameter "amount" is empty`)
+	assert.Equal(suite.T(), `2 validation errors: transfer 0: parameter "to" is empty; transfer 2: parameter "amount" is empty`, ve.Error())
+}
+
+func (suite *ValidationTestSuite) TestValidationErrorOrNil() {
+	ve := &ValidationError{}
+	assert.Nil(suite.T(), ve.errorOrNil())
+	ve.add(0, "to", `parameter "to" is empty`)
+	assert.Equal(suite.T(), ve, ve.errorOrNil())
+}
+
+func (suite *ValidationTestSuite) TestCreateTransferRequestAllViolations() {
+	transfer := &CreateTransferRequestParams{
+		Id:       strings.Repeat("1", 51),
+		To:       "89680",
+		Amount:   MustParseAmount("-10"),
+		Currency: "CHY",
+		FeeMode:  "FiX",
+		Note:     strings.Repeat("n", 256),
+		Ref:      strings.Repeat("r", 51),
+	}
+	err := transfer.isValid()
+	var ve *ValidationError
+	assert.True(suite.T(), errors.As(err, &ve))
+	assert.Len(suite.T(), ve.Errors, 7)
+	assert.Equal(suite.T(), `parameter "id" is longer than 50 characters`, ve.Errors[0].Message)
+	assert.Equal(suite.T(), `parameter "to" has invalid format, FPnnnnn expected`, ve.Errors[1].Message)
+	assert.Equal(suite.T(), `parameter "fee_mode" is not supported`, ve.Errors[2].Message)
+	assert.Equal(suite.T(), `parameter "note" is longer than 255 characters`, ve.Errors[3].Message)
+	assert.Equal(suite.T(), `parameter "ref" is longer than 50 characters`, ve.Errors[4].Message)
+	assert.Equal(suite.T(), `parameter "currency" is not supported`, ve.Errors[5].Message)
+	assert.Equal(suite.T(), `parameter "amount" must be positive`, ve.Errors[6].Message)
+}
+
+func (suite *ValidationTestSuite) TestCreateTransferRequestAmountDecimals() {
+	transfer := &CreateTransferRequestParams{To: "FP89680", Amount: MustParseAmount("10.001"), Currency: CurrencyCodeUSD}
+	assert.Equal(suite.T(), `parameter "amount" has more than 2 decimal places`, transfer.isValid().Error())
+	transfer.Amount = MustParseAmount("10.010")
+	assert.NoError(suite.T(), transfer.isValid())
+}
+
+func (suite *ValidationTestSuite) TestCreateTransferRequestAmountLimits() {
+	_ = RegisterCurrency(&Currency{Code: "EUR", Precision: 2, MinTransfer: MustParseAmount("1"), MaxTransfer: MustParseAmount("100")})
+
+	transfer := &CreateTransferRequestParams{To: "FP89680", Amount: MustParseAmount("0.5"), Currency: "EUR"}
+	assert.Equal(suite.T(), `parameter "amount" is less than min transfer 1 EUR`, transfer.isValid().Error())
+	transfer.Amount = MustParseAmount("100.01")
+	assert.Equal(suite.T(), `parameter "amount" is greater than max transfer 100 EUR`, transfer.isValid().Error())
+	transfer.Amount = MustParseAmount("100")
+	assert.NoError(suite.T(), transfer.isValid())
+}
+
+func (suite *ValidationTestSuite) TestCreateTransferRequestFeeModes() {
+	transfer := &CreateTransferRequestParams{To: "FP89680", Amount: MustParseAmount("10"), Currency: CurrencyCodeUSD}
+	for _, feeMode := range []TransactionFeeMode{"", TransactionFeeModeFiR, TransactionFeeModeFiS} {
+		transfer.FeeMode = feeMode
+		assert.NoError(suite.T(), transfer.isValid())
+	}
+}
+
+func TestValidationTestSuite(t *testing.T) {
+	suite.Run(t, new(ValidationTestSuite))
+}
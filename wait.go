@@ -0,0 +1,80 @@
+package fasapay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+//ErrTransactionUnsuccessful transaction reached terminal status other than FINISH
+var ErrTransactionUnsuccessful = errors.New("transaction is not successful")
+
+//PollPolicy struct - intervals between transaction status checks
+type PollPolicy struct {
+	InitialInterval time.Duration //delay before the second check
+	MaxInterval     time.Duration //max delay between checks
+	Multiplier      float64       //interval multiplier applied after each check
+}
+
+//NewPollPolicy Create new poll policy with default parameters
+func NewPollPolicy() *PollPolicy {
+	return &PollPolicy{
+		InitialInterval: time.Second,
+		MaxInterval:     30 * time.Second,
+		Multiplier:      1.5,
+	}
+}
+
+//interval method - delay after check number attempt (starts from 1)
+func (pp *PollPolicy) interval(attempt int) time.Duration {
+	return time.Duration(exponentialDelay(pp.InitialInterval, pp.MaxInterval, pp.Multiplier, attempt))
+}
+
+//WaitForTransfer method - poll transaction details until the transaction reaches terminal status or ctx is done.
+//Transaction not found yet and transient network errors do not stop polling. NewPollPolicy is used if policy is nil.
+//Terminal status other than FINISH is returned together with ErrTransactionUnsuccessful.
+//On ctx expiration the last received details are returned together with the error.
+func (r *TransfersResource) WaitForTransfer(batchNumber string, ctx context.Context, policy *PollPolicy) (*GetDetailsResponseDetailParams, error) {
+	if batchNumber == "" {
+		return nil, fmt.Errorf(`TransfersResource.WaitForTransfer error: parameter "batchnumber" is empty`)
+	}
+	if policy == nil {
+		policy = NewPollPolicy()
+	}
+	detail := GetDetailsRequestDetailParamsString(batchNumber)
+	details := []GetDetailsDetailParamsInterface{&detail}
+	classifier := r.tr.retry
+	if classifier == nil {
+		classifier = NewRetryPolicy()
+	}
+	var last *GetDetailsResponseDetailParams
+	for attempt := 1; ; attempt++ {
+		result, rsp, err := r.GetDetails(details, ctx, nil)
+		var apiErr *APIError
+		switch {
+		case err == nil:
+			if len(result.Details) > 0 {
+				last = result.Details[0]
+				if last.Status.IsSuccessful() {
+					return last, nil
+				}
+				if last.Status.IsTerminal() {
+					return last, fmt.Errorf(`TransfersResource.WaitForTransfer error: transaction "%s" status "%s": %w`, batchNumber, last.Status, ErrTransactionUnsuccessful)
+				}
+			}
+		case errors.Is(err, ErrTransactionNotFound), ctx.Err() != nil:
+		case errors.As(err, &apiErr):
+			return last, err
+		case !isRetryableError(classifier, rsp, err):
+			return last, fmt.Errorf("TransfersResource.WaitForTransfer error: %w", err)
+		}
+		timer := time.NewTimer(policy.interval(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return last, fmt.Errorf("TransfersResource.WaitForTransfer error: %w", ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package fasapay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+type PollPolicyTestSuite struct {
+	suite.Suite
+}
+
+func (suite *PollPolicyTestSuite) TestNewPollPolicy() {
+	policy := NewPollPolicy()
+	assert.Equal(suite.T(), time.Second, policy.InitialInterval)
+	assert.Equal(suite.T(), 30*time.Second, policy.MaxInterval)
+	assert.Equal(suite.T(), 1.5, policy.Multiplier)
+}
+
+func (suite *PollPolicyTestSuite) TestInterval() {
+	policy := &PollPolicy{InitialInterval: time.Second, MaxInterval: 3 * time.Second, Multiplier: 2}
+	assert.Equal(suite.T(), time.Second, policy.interval(1))
+	assert.Equal(suite.T(), 2*time.Second, policy.interval(2))
+	assert.Equal(suite.T(), 3*time.Second, policy.interval(3))
+	policy.Multiplier = 0
+	assert.Equal(suite.T(), time.Second, policy.interval(3))
+}
+
+func TestPollPolicyTestSuite(t *testing.T) {
+	suite.Run(t, new(PollPolicyTestSuite))
+}
+
+type TransactionStatusTestSuite struct {
+	suite.Suite
+}
+
+func (suite *TransactionStatusTestSuite) TestIsTerminal() {
+	assert.True(suite.T(), TransactionStatusFinish.IsTerminal())
+	assert.True(suite.T(), TransactionStatus(" finish ").IsTerminal())
+	assert.True(suite.T(), TransactionStatusCancel.IsTerminal())
+	assert.True(suite.T(), TransactionStatus("failed").IsTerminal())
+	assert.False(suite.T(), TransactionStatus("PENDING").IsTerminal())
+	assert.False(suite.T(), TransactionStatus("UNKNOWN").IsTerminal())
+	assert.False(suite.T(), TransactionStatus("").IsTerminal())
+}
+
+func (suite *TransactionStatusTestSuite) TestIsSuccessful() {
+	assert.True(suite.T(), TransactionStatusFinish.IsSuccessful())
+	assert.True(suite.T(), TransactionStatus("Finish").IsSuccessful())
+	assert.False(suite.T(), TransactionStatusCancel.IsSuccessful())
+	assert.False(suite.T(), TransactionStatusFailed.IsSuccessful())
+	assert.False(suite.T(), TransactionStatus("UNKNOWN").IsSuccessful())
+}
+
+func TestTransactionStatusTestSuite(t *testing.T) {
+	suite.Run(t, new(TransactionStatusTestSuite))
+}
+
+type WaitForTransferTestSuite struct {
+	suite.Suite
+	cfg      *Config
+	ctx      context.Context
+	testable *TransfersResource
+	policy   *PollPolicy
+}
+
+func (suite *WaitForTransferTestSuite) SetupTest() {
+	cfg := BuildStubConfig()
+	transport := BuildStubHttpTransport()
+	suite.cfg = cfg
+	suite.ctx = context.Background()
+	suite.testable = &TransfersResource{ResourceAbstract: NewResourceAbstract(transport, cfg)}
+	suite.policy = &PollPolicy{InitialInterval: time.Millisecond}
+	httpmock.Activate()
+}
+
+func (suite *WaitForTransferTestSuite) TearDownTest() {
+	httpmock.DeactivateAndReset()
+}
+
+//registerSequence method - respond with the given responders one by one, the last one is repeated
+func (suite *WaitForTransferTestSuite) registerSequence(responders ...httpmock.Responder) {
+	calls := 0
+	httpmock.RegisterResponder(http.MethodPost, suite.cfg.Uri, func(req *http.Request) (*http.Response, error) {
+		responder := responders[len(responders)-1]
+		if calls < len(responders) {
+			responder = responders[calls]
+		}
+		calls++
+		return responder(req)
+	})
+}
+
+//buildDetailsResponder method - details stub with the given status
+func (suite *WaitForTransferTestSuite) buildDetailsResponder(status TransactionStatus) httpmock.Responder {
+	body, _ := LoadStubResponseData("stubs/transfers/details/success.xml")
+	return httpmock.NewStringResponder(http.StatusOK, strings.Replace(string(body), "FINISH", string(status), 1))
+}
+
+func (suite *WaitForTransferTestSuite) TestWaitForTransferFinished() {
+	notFound, _ := LoadStubResponseData("stubs/transfers/details/error.xml")
+	suite.registerSequence(
+		httpmock.NewBytesResponder(http.StatusOK, notFound),
+		httpmock.NewErrorResponder(fmt.Errorf("connection reset")),
+		suite.buildDetailsResponder("PENDING"),
+		suite.buildDetailsResponder(TransactionStatusFinish),
+	)
+	result, err := suite.testable.WaitForTransfer("TR2012092791234", suite.ctx, suite.policy)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), TransactionStatusFinish, result.Status)
+	assert.True(suite.T(), result.Status.IsSuccessful())
+	assert.Equal(suite.T(), 4, httpmock.GetTotalCallCount())
+}
+
+func (suite *WaitForTransferTestSuite) TestWaitForTransferContextExpired() {
+	suite.registerSequence(suite.buildDetailsResponder("PENDING"))
+	ctx, cancel := context.WithTimeout(suite.ctx, 50*time.Millisecond)
+	defer cancel()
+	result, err := suite.testable.WaitForTransfer("TR2012092791234", ctx, suite.policy)
+	assert.True(suite.T(), errors.Is(err, context.DeadlineExceeded))
+	assert.Equal(suite.T(), TransactionStatus("PENDING"), result.Status)
+	assert.True(suite.T(), httpmock.GetTotalCallCount() > 1)
+}
+
+func (suite *WaitForTransferTestSuite) TestWaitForTransferFailed() {
+	suite.registerSequence(
+		suite.buildDetailsResponder("PENDING"),
+		suite.buildDetailsResponder(TransactionStatusFailed),
+		suite.buildDetailsResponder(TransactionStatusFinish),
+	)
+	result, err := suite.testable.WaitForTransfer("TR2012092791234", suite.ctx, suite.policy)
+	assert.True(suite.T(), errors.Is(err, ErrTransactionUnsuccessful))
+	assert.Equal(suite.T(), `TransfersResource.WaitForTransfer error: transaction "TR2012092791234" status "FAILED": transaction is not successful`, err.Error())
+	assert.Equal(suite.T(), TransactionStatusFailed, result.Status)
+	assert.Equal(suite.T(), 2, httpmock.GetTotalCallCount())
+}
+
+func (suite *WaitForTransferTestSuite) TestWaitForTransferApiError() {
+	body, _ := LoadStubResponseData("stubs/accounts/details/error.xml")
+	suite.registerSequence(httpmock.NewBytesResponder(http.StatusOK, body))
+	result, err := suite.testable.WaitForTransfer("TR2012092791234", suite.ctx, suite.policy)
+	assert.Nil(suite.T(), result)
+	assert.True(suite.T(), errors.Is(err, ErrAccountNotFound))
+	assert.Equal(suite.T(), 1, httpmock.GetTotalCallCount())
+}
+
+func (suite *WaitForTransferTestSuite) TestWaitForTransferHttpError() {
+	suite.registerSequence(httpmock.NewStringResponder(http.StatusBadRequest, "Bad request"))
+	_, err := suite.testable.WaitForTransfer("TR2012092791234", suite.ctx, suite.policy)
+	var httpErr *HTTPError
+	assert.True(suite.T(), errors.As(err, &httpErr))
+	assert.Equal(suite.T(), 1, httpmock.GetTotalCallCount())
+}
+
+func (suite *WaitForTransferTestSuite) TestWaitForTransferEmptyBatchNumber() {
+	_, err := suite.testable.WaitForTransfer("", suite.ctx, nil)
+	assert.Equal(suite.T(), `TransfersResource.WaitForTransfer error: parameter "batchnumber" is empty`, err.Error())
+}
+
+func TestWaitForTransferTestSuite(t *testing.T) {
+	suite.Run(t, new(WaitForTransferTestSuite))
+}